@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -14,13 +15,11 @@ import (
 	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
 )
 
-var GlobalCounter int = 0
-
 var unmeshedClient *apis.UnmeshedClient
 
 type MathOperations struct{}
 
-func (m *MathOperations) Sum(data map[string]int) int {
+func (m *MathOperations) SumWorker(data map[string]int) int {
 	sum := 0
 	for _, v := range data {
 		sum += v
@@ -28,21 +27,20 @@ func (m *MathOperations) Sum(data map[string]int) int {
 	return sum
 }
 
-func RescheduleExample(data map[string]interface{}) *common.StepResult {
-	fmt.Println("Reschedule worker running with counter", GlobalCounter)
-	if GlobalCounter > 5 {
-		stepResult := common.NewStepResult("Response after 5 iterations")
-		stepResult.KeepRunning = false
-		stepResult.RescheduleAfterSeconds = 0
-		GlobalCounter = 0
-		return stepResult
+type RescheduleState struct {
+	Iterations int `json:"iterations"`
+}
 
+func RescheduleExample(ctx context.Context, data map[string]interface{}, state *RescheduleState) (*common.StepResult, error) {
+	fmt.Println("Reschedule worker running with counter", state.Iterations)
+	if state.Iterations > 5 {
+		return common.NewStepResult("Response after 5 iterations"), nil
 	}
-	GlobalCounter++
+	state.Iterations++
 	stepResult := common.NewStepResult("testing reschedule")
 	stepResult.KeepRunning = true
 	stepResult.RescheduleAfterSeconds = 2
-	return stepResult
+	return stepResult, nil
 }
 
 func FailExample(data map[string]interface{}) error {
@@ -131,6 +129,14 @@ func FlattenValues(data map[string][]int) []int {
 	return result
 }
 
+type GreetInput struct {
+	Name string `json:"name"`
+}
+
+func Greet(ctx context.Context, in GreetInput) (string, error) {
+	return fmt.Sprintf("Hello, %s!", in.Name), nil
+}
+
 func ManuallyRegisteredWorker(data map[string]interface{}) string {
 	return "Test"
 }
@@ -141,28 +147,34 @@ func MultiOutputExample(data map[string]interface{}) (string, int) {
 	return message, count
 }
 
-func DelayedResponse(data map[string]interface{}) string {
-	time.Sleep(10 * time.Second)
-	return "Response after 10 seconds delay"
+func DelayedResponse(ctx context.Context, data map[string]interface{}) (string, error) {
+	stepContext := common.StepContextFrom(ctx)
+	for second := 1; second <= 10; second++ {
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if err := stepContext.ReportProgress(map[string]interface{}{"secondsWaited": second}); err != nil {
+			stepContext.Logger.Printf("Could not report progress: %v", err)
+		}
+	}
+	return "Response after 10 seconds delay", nil
 }
 
-func PrintCurrentWorkRequest(data map[string]interface{}) string {
-	if unmeshedClient != nil {
-		current := unmeshedClient.GetCurrentWorkRequest()
-		if current != nil {
-			fmt.Printf("Current WorkRequest in goroutine: %+v\n", current)
-			return "Printed current work request to stdout"
-		}
+func PrintCurrentWorkRequest(ctx context.Context, data map[string]interface{}) string {
+	stepContext := common.StepContextFrom(ctx)
+	if stepContext == nil {
 		return "No current work request found"
 	}
-	return "unmeshedClient is nil"
+	stepContext.Logger.Printf("Current WorkRequest: %+v", stepContext.WorkRequest)
+	return "Printed current work request to the log"
 }
 
 func main() {
 	workerList := []*apis2.Worker{
-		apis2.NewWorker(RescheduleExample, "reschedule-example"),
+		apis2.NewStatefulWorker("reschedule-example", RescheduleExample),
 		apis2.NewWorker(DelayedResponse, "delayed-response"),
-		apis2.NewWorker(MultiOutputExample, "multi-output-example"),
 		apis2.NewWorker(ListExample, "list-example"),
 		apis2.NewWorker(FailExample, "fail-example"),
 		apis2.NewWorker(ProcessMap, "process-map"),
@@ -174,6 +186,7 @@ func main() {
 		apis2.NewWorker(ReverseMap, "reverse_map"),
 		apis2.NewWorker(FlattenValues, "flatten_values"),
 		apis2.NewWorker(PrintCurrentWorkRequest, "print-current-work-request"),
+		apis2.NewTypedWorker("greet", Greet),
 	}
 
 	clientConfig := configs.NewClientConfig()
@@ -198,6 +211,16 @@ func main() {
 	worker := apis2.NewWorker(ManuallyRegisteredWorker, "manually-registered-worker")
 	unmeshedClient.RegisterWorker(worker)
 
+	// Produces {"message": ..., "count": ...} instead of {"result": [...]}
+	multiOutputWorker := apis2.NewWorker(MultiOutputExample, "multi-output-example")
+	multiOutputWorker.SetOutputNames("message", "count")
+	unmeshedClient.RegisterWorker(multiOutputWorker)
+
+	// Registers MathOperations.SumWorker as the worker "sum"
+	if err := unmeshedClient.RegisterWorkerStruct(&MathOperations{}, nil); err != nil {
+		fmt.Printf("Error registering workers: %v\n", err)
+	}
+
 	done := make(chan struct{})
 
 	///Start the client in goroutine
@@ -348,6 +371,13 @@ func main() {
 	select {
 	case <-sigChan:
 		fmt.Println("\nReceived shutdown signal. Stopping client...")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		summary, err := unmeshedClient.Shutdown(ctx)
+		if err != nil {
+			fmt.Printf("Shutdown incomplete: %d steps running, %d results not delivered\n",
+				summary.InFlightSteps, len(summary.Undelivered))
+		}
 	case <-done:
 		fmt.Println("Client finished execution")
 	}
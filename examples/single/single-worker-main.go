@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -283,6 +284,13 @@ func main() {
 	select {
 	case <-sigChan:
 		fmt.Println("\nReceived shutdown signal. Stopping client...")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		summary, err := unmeshedClient.Shutdown(ctx)
+		if err != nil {
+			fmt.Printf("Shutdown incomplete: %d steps running, %d results not delivered\n",
+				summary.InFlightSteps, len(summary.Undelivered))
+		}
 	case <-done:
 		fmt.Println("Client finished execution")
 	}
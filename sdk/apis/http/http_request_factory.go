@@ -3,6 +3,7 @@ package apis
 import (
 	"bytes"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,7 +25,7 @@ type HttpRequestFactory struct {
 func NewHttpRequestFactory(clientConfig *configs.ClientConfig) *HttpRequestFactory {
 	hashedToken, err := utils.CreateSecureHash(clientConfig.AuthToken)
 	if err != nil {
-		fmt.Errorf("error creating secure hash for token: %w", err)
+		log.Printf("error creating secure hash for token: %v", err)
 	}
 
 	bearerValue := fmt.Sprintf("Bearer client.sdk.%s.%s",
@@ -0,0 +1,100 @@
+package apis
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// dedupCache remembers the step executions this client is working on so a work
+// request delivered twice is not executed twice. An entry lives while its step
+// executes and until the server acknowledges the result. The ttl only limits
+// how long a finished step waits for that acknowledgement, and size bounds the
+// number of entries, evicting the oldest first.
+type dedupCache struct {
+	size      int
+	ttl       time.Duration
+	lock      sync.Mutex
+	entries   map[int64]*list.Element
+	order     *list.List
+	lastSweep time.Time
+}
+
+type dedupEntry struct {
+	stepExecutionID int64
+	// expiresAt stays zero while the step is executing.
+	expiresAt time.Time
+}
+
+func newDedupCache(size int, ttl time.Duration) *dedupCache {
+	return &dedupCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[int64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// markSeen records a step execution and reports whether it was already seen.
+func (c *dedupCache) markSeen(stepExecutionID int64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evictExpired(time.Now())
+	if _, exists := c.entries[stepExecutionID]; exists {
+		return true
+	}
+	c.entries[stepExecutionID] = c.order.PushBack(&dedupEntry{stepExecutionID: stepExecutionID})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Front())
+	}
+	return false
+}
+
+// finished records that a step execution has stopped running and its result
+// is waiting for acknowledgement, which starts its ttl.
+func (c *dedupCache) finished(stepExecutionID int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, exists := c.entries[stepExecutionID]; exists {
+		entry := element.Value.(*dedupEntry)
+		if entry.expiresAt.IsZero() {
+			entry.expiresAt = time.Now().Add(c.ttl)
+		}
+	}
+}
+
+// forget removes a step execution, so its next delivery is executed again.
+func (c *dedupCache) forget(stepExecutionID int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, exists := c.entries[stepExecutionID]; exists {
+		c.remove(element)
+	}
+}
+
+func (c *dedupCache) evictExpired(now time.Time) {
+	// Finished entries are spread across the list, so sweep at most once per
+	// ttl or second instead of on every delivery.
+	interval := c.ttl
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if now.Sub(c.lastSweep) < interval {
+		return
+	}
+	c.lastSweep = now
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*dedupEntry)
+		if !entry.expiresAt.IsZero() && !entry.expiresAt.After(now) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *dedupCache) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*dedupEntry).stepExecutionID)
+	c.order.Remove(element)
+}
@@ -0,0 +1,87 @@
+package apis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	workersApi "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestDedupCache_DetectsDuplicates(t *testing.T) {
+	cache := newDedupCache(2, time.Minute)
+
+	assert.False(t, cache.markSeen(1))
+	assert.True(t, cache.markSeen(1))
+	assert.False(t, cache.markSeen(2))
+
+	// The oldest entry is evicted once the cache is full.
+	assert.False(t, cache.markSeen(3))
+	assert.False(t, cache.markSeen(1))
+
+	cache.forget(3)
+	assert.False(t, cache.markSeen(3))
+}
+
+func TestDedupCache_OnlyFinishedEntriesExpire(t *testing.T) {
+	cache := newDedupCache(10, 20*time.Millisecond)
+
+	assert.False(t, cache.markSeen(1))
+	assert.False(t, cache.markSeen(2))
+	cache.finished(2)
+	time.Sleep(30 * time.Millisecond)
+
+	// Step 1 is still running however long it takes; step 2 never got its
+	// result acknowledged and is given up on.
+	assert.True(t, cache.markSeen(1))
+	assert.False(t, cache.markSeen(2))
+	assert.Len(t, cache.entries, 2)
+}
+
+func TestSubmitWorkResponse_ForgetsDeliveryOnceSubmitted(t *testing.T) {
+	uc := &UnmeshedClient{
+		workResponseBuilder: common.NewWorkResponseBuilder(),
+		dedup:               newDedupCache(10, time.Minute),
+	}
+	workRequest := common.NewWorkRequest()
+	workRequest.StepExecutionID = 9
+	assert.False(t, uc.dedup.markSeen(9))
+
+	// Without a submit client there is nothing to wait for.
+	uc.executingCount.Store(1)
+	uc.handleWorkCompletion(common.NewStepPollState(1), workRequest, common.NewStepResult("done"), nil)
+	assert.False(t, uc.dedup.markSeen(9))
+}
+
+func TestDispatch_SkipsDuplicateDeliveries(t *testing.T) {
+	worker := workersApi.NewWorker(func(data map[string]interface{}) string { return "ok" }, "dedup-worker")
+	workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+	state := common.NewStepPollState(2)
+	uc := &UnmeshedClient{
+		workersByID: map[string]*workersApi.Worker{workerId: worker},
+		pollStates:  map[string]*common.StepPollState{workerId: state},
+		dedup:       newDedupCache(10, time.Minute),
+	}
+	var executed []int64
+	uc.sharedPool = newExecutionPool("shared", 1, 10, 0, func(task stepTask) {
+		executed = append(executed, task.workRequest.GetStepExecutionID())
+	})
+
+	workRequest := common.NewWorkRequest()
+	workRequest.StepName = worker.GetName()
+	workRequest.SetStepNamespace(worker.GetNamespace())
+	workRequest.StepExecutionID = 7
+	assert.Equal(t, 2, state.AcquireMaxAvailable())
+	uc.executingCount.Store(2)
+
+	uc.dispatch(*workRequest)
+	uc.dispatch(*workRequest)
+	uc.sharedPool.close()
+	uc.sharedPool.wait()
+
+	assert.Equal(t, []int64{7}, executed)
+	assert.Equal(t, 1, state.MaxAvailable())
+	assert.Equal(t, int32(1), uc.executingCount.Load())
+	assert.Equal(t, int64(1), uc.GetRuntimeStats().DuplicatesSeen)
+}
@@ -0,0 +1,112 @@
+package apis
+
+import (
+	"container/list"
+	"errors"
+	"time"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+// maxTrackedDeferredSteps bounds the deferred steps listed in GetRuntimeStats.
+const maxTrackedDeferredSteps = 1000
+
+// DeferredStep is a step this client deferred and has not finished yet.
+type DeferredStep struct {
+	Token      common.CompletionToken
+	DeferredAt time.Time
+}
+
+// CompleteStep finishes a deferred step as COMPLETED with the given output.
+// The result is submitted right away instead of being queued.
+func (uc *UnmeshedClient) CompleteStep(processID, stepID, stepExecutionID int64, output map[string]interface{}) error {
+	return uc.finishStep(processID, stepID, stepExecutionID, output, common.StepStatusCompleted)
+}
+
+// FailStep finishes a deferred step as FAILED with the given output.
+func (uc *UnmeshedClient) FailStep(processID, stepID, stepExecutionID int64, output map[string]interface{}) error {
+	return uc.finishStep(processID, stepID, stepExecutionID, output, common.StepStatusFailed)
+}
+
+// CompleteStepWithToken is CompleteStep for the step identified by token.
+func (uc *UnmeshedClient) CompleteStepWithToken(token common.CompletionToken, output map[string]interface{}) error {
+	return uc.CompleteStep(token.ProcessID, token.StepID, token.StepExecutionID, output)
+}
+
+// FailStepWithToken is FailStep for the step identified by token.
+func (uc *UnmeshedClient) FailStepWithToken(token common.CompletionToken, output map[string]interface{}) error {
+	return uc.FailStep(token.ProcessID, token.StepID, token.StepExecutionID, output)
+}
+
+func (uc *UnmeshedClient) finishStep(processID, stepID, stepExecutionID int64, output map[string]interface{}, status common.StepStatus) error {
+	if uc.submitClient == nil {
+		return errors.New("results submission is disabled")
+	}
+	if output == nil {
+		output = map[string]interface{}{}
+	}
+	workResponse := common.NewWorkResponse()
+	workResponse.SetProcessID(processID)
+	workResponse.SetStepID(stepID)
+	workResponse.SetStepExecutionID(stepExecutionID)
+	workResponse.SetOutput(output)
+	workResponse.SetStartedAt(time.Now().UnixMilli())
+	workResponse.SetStatus(status)
+	if err := uc.submitClient.SubmitSync(workResponse); err != nil {
+		return err
+	}
+	uc.stepFinished(common.CompletionToken{ProcessID: processID, StepID: stepID, StepExecutionID: stepExecutionID})
+	return nil
+}
+
+// stepDeferred records a deferred step, so it shows up in GetRuntimeStats
+// until it is finished through this client. Steps finished elsewhere are never
+// seen again here, so only the most recent maxTrackedDeferredSteps are kept.
+func (uc *UnmeshedClient) stepDeferred(workRequest *common.WorkRequest) {
+	token := common.NewCompletionToken(workRequest)
+
+	uc.deferredLock.Lock()
+	defer uc.deferredLock.Unlock()
+	if uc.deferredSteps == nil {
+		uc.deferredSteps = make(map[common.CompletionToken]*list.Element)
+		uc.deferredOrder = list.New()
+	}
+	if element, exists := uc.deferredSteps[token]; exists {
+		uc.deferredOrder.Remove(element)
+	}
+	uc.deferredSteps[token] = uc.deferredOrder.PushBack(DeferredStep{Token: token, DeferredAt: time.Now()})
+	for uc.deferredOrder.Len() > maxTrackedDeferredSteps {
+		oldest := uc.deferredOrder.Front()
+		uc.deferredOrder.Remove(oldest)
+		delete(uc.deferredSteps, oldest.Value.(DeferredStep).Token)
+	}
+}
+
+func (uc *UnmeshedClient) stepFinished(token common.CompletionToken) {
+	uc.deferredLock.Lock()
+	defer uc.deferredLock.Unlock()
+	if element, exists := uc.deferredSteps[token]; exists {
+		uc.deferredOrder.Remove(element)
+		delete(uc.deferredSteps, token)
+	}
+}
+
+// pendingDeferredSteps returns the deferred steps, oldest first.
+func (uc *UnmeshedClient) pendingDeferredSteps() []DeferredStep {
+	uc.deferredLock.Lock()
+	defer uc.deferredLock.Unlock()
+	steps := make([]DeferredStep, 0, len(uc.deferredSteps))
+	if uc.deferredOrder == nil {
+		return steps
+	}
+	for element := uc.deferredOrder.Front(); element != nil; element = element.Next() {
+		steps = append(steps, element.Value.(DeferredStep))
+	}
+	return steps
+}
+
+func (uc *UnmeshedClient) deferredStepCount() int {
+	uc.deferredLock.Lock()
+	defer uc.deferredLock.Unlock()
+	return len(uc.deferredSteps)
+}
@@ -0,0 +1,61 @@
+package apis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestHandleWorkCompletion_DeferredReleasesPermit(t *testing.T) {
+	uc := &UnmeshedClient{workResponseBuilder: common.NewWorkResponseBuilder()}
+	state := common.NewStepPollState(2)
+	assert.Equal(t, 2, state.AcquireMaxAvailable())
+	uc.executingCount.Store(2)
+
+	uc.handleWorkCompletion(state, common.NewWorkRequest(), common.NewDeferredStepResult(), nil)
+	assert.Equal(t, 1, state.MaxAvailable())
+	assert.Equal(t, int32(1), uc.executingCount.Load())
+}
+
+func TestHandleWorkCompletion_DeferredIsTrackedAndNotDeduplicated(t *testing.T) {
+	uc := &UnmeshedClient{
+		workResponseBuilder: common.NewWorkResponseBuilder(),
+		dedup:               newDedupCache(10, time.Minute),
+	}
+	workRequest := common.NewWorkRequest()
+	workRequest.ProcessID = 10
+	workRequest.StepID = 1
+	workRequest.StepExecutionID = 100
+	assert.False(t, uc.dedup.markSeen(100))
+
+	state := common.NewStepPollState(1)
+	state.AcquireMaxAvailable()
+	uc.executingCount.Store(1)
+	uc.handleWorkCompletion(state, workRequest, common.NewDeferredStepResult(), nil)
+
+	assert.False(t, uc.dedup.markSeen(100), "a redelivered deferred step runs again")
+
+	deferred := uc.GetRuntimeStats().DeferredSteps
+	assert.Len(t, deferred, 1)
+	assert.Equal(t, common.NewCompletionToken(workRequest), deferred[0].Token)
+
+	uc.stepFinished(common.NewCompletionToken(workRequest))
+	assert.Empty(t, uc.GetRuntimeStats().DeferredSteps)
+}
+
+func TestStepDeferred_KeepsMostRecentSteps(t *testing.T) {
+	uc := &UnmeshedClient{}
+	for i := 1; i <= maxTrackedDeferredSteps+5; i++ {
+		workRequest := common.NewWorkRequest()
+		// Requests without a step execution ID are told apart by their step.
+		workRequest.StepID = int64(i)
+		uc.stepDeferred(workRequest)
+	}
+
+	deferred := uc.pendingDeferredSteps()
+	assert.Len(t, deferred, maxTrackedDeferredSteps)
+	assert.Equal(t, int64(6), deferred[0].Token.StepID)
+	assert.Equal(t, int64(maxTrackedDeferredSteps+5), deferred[len(deferred)-1].Token.StepID)
+}
@@ -0,0 +1,109 @@
+package apis
+
+import (
+	"sync"
+
+	workersApi "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+type stepTask struct {
+	worker      *workersApi.Worker
+	state       *common.StepPollState
+	workRequest common.WorkRequest
+}
+
+// executionPool runs step tasks on a fixed number of goroutines. Workers with
+// a dedicated pool are isolated from the shared one, so a slow step type cannot
+// occupy every goroutine. Queued tasks are started in priority order.
+type executionPool struct {
+	name       string
+	size       int
+	queueDepth int
+	run        func(task stepTask)
+	lock       sync.Mutex
+	ready      *sync.Cond
+	queue      *priorityQueue
+	running    int
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+func newExecutionPool(name string, size int, queueDepth int, agingMillis int64, run func(task stepTask)) *executionPool {
+	pool := &executionPool{
+		name:       name,
+		size:       size,
+		queueDepth: queueDepth,
+		run:        run,
+		queue:      newPriorityQueue(agingMillis),
+	}
+	pool.ready = sync.NewCond(&pool.lock)
+	for i := 0; i < size; i++ {
+		pool.wg.Add(1)
+		go pool.loop()
+	}
+	return pool
+}
+
+func (p *executionPool) loop() {
+	defer p.wg.Done()
+	for {
+		p.lock.Lock()
+		for p.queue.Len() == 0 && !p.closed {
+			p.ready.Wait()
+		}
+		if p.queue.Len() == 0 {
+			p.lock.Unlock()
+			return
+		}
+		task := p.queue.pop()
+		p.running++
+		p.lock.Unlock()
+
+		p.run(task)
+
+		p.lock.Lock()
+		p.running--
+		p.lock.Unlock()
+	}
+}
+
+// enqueue hands a task to the pool. It never blocks; callers bound the number
+// of outstanding tasks through freeCapacity and the step permits. It returns
+// false without queueing the task when the pool has been closed, as its
+// goroutines may already have exited.
+func (p *executionPool) enqueue(task stepTask) bool {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return false
+	}
+	p.queue.push(task)
+	p.lock.Unlock()
+	p.ready.Signal()
+	return true
+}
+
+// freeCapacity returns how many more tasks the pool can take without exceeding
+// its size plus queue depth.
+func (p *executionPool) freeCapacity() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	free := p.size + p.queueDepth - p.queue.Len() - p.running
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+// close stops the pool once all queued tasks have been executed.
+func (p *executionPool) close() {
+	p.lock.Lock()
+	p.closed = true
+	p.lock.Unlock()
+	p.ready.Broadcast()
+}
+
+func (p *executionPool) wait() {
+	p.wg.Wait()
+}
@@ -0,0 +1,111 @@
+package apis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	workersApi "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestExecutionPool_RunsAllTasksBeforeClosing(t *testing.T) {
+	var lock sync.Mutex
+	executed := map[int64]bool{}
+	pool := newExecutionPool("test", 2, 4, 0, func(task stepTask) {
+		lock.Lock()
+		executed[task.workRequest.StepID] = true
+		lock.Unlock()
+	})
+
+	for i := int64(1); i <= 5; i++ {
+		pool.enqueue(stepTask{workRequest: common.WorkRequest{StepID: i}})
+	}
+	pool.close()
+	pool.wait()
+
+	assert.Len(t, executed, 5)
+}
+
+func TestExecutionPool_FreeCapacity(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool := newExecutionPool("test", 1, 2, 0, func(task stepTask) {
+		started <- struct{}{}
+		<-release
+	})
+	assert.Equal(t, 3, pool.freeCapacity())
+
+	pool.enqueue(stepTask{})
+	<-started
+	pool.enqueue(stepTask{})
+	assert.Equal(t, 1, pool.freeCapacity())
+
+	close(release)
+	<-started
+	pool.close()
+	pool.wait()
+	assert.Equal(t, 3, pool.freeCapacity())
+}
+
+func TestExecutionPool_RejectsTasksAfterClose(t *testing.T) {
+	pool := newExecutionPool("test", 1, 1, 0, func(task stepTask) {})
+	pool.close()
+	pool.wait()
+
+	assert.False(t, pool.enqueue(stepTask{}))
+	assert.Equal(t, 2, pool.freeCapacity())
+}
+
+func TestDispatch_ClosedPoolReleasesStep(t *testing.T) {
+	worker := workersApi.NewWorker(func() {}, "removed")
+	worker.SetNamespace("default")
+	pool := newExecutionPool("removed", 1, 1, 0, func(task stepTask) {
+		t.Error("task ran on a closed pool")
+	})
+	pool.close()
+	pool.wait()
+
+	workerId := formattedWorkerID("default", "removed")
+	state := common.NewStepPollState(1)
+	assert.Equal(t, 1, state.AcquireMaxAvailable())
+	uc := &UnmeshedClient{
+		workersByID:    map[string]*workersApi.Worker{workerId: worker},
+		pollStates:     map[string]*common.StepPollState{workerId: state},
+		dedicatedPools: map[string]*executionPool{workerId: pool},
+		dedup:          newDedupCache(10, time.Minute),
+	}
+	uc.executingCount.Store(1)
+
+	uc.dispatch(common.WorkRequest{StepNamespace: "default", StepName: "removed", StepExecutionID: 7})
+
+	assert.Equal(t, 1, state.MaxAvailable())
+	assert.Equal(t, int32(0), uc.executingCount.Load())
+	assert.False(t, uc.dedup.markSeen(7), "a redelivery is not treated as a duplicate")
+}
+
+func TestPriorityQueue_HigherPriorityFirst(t *testing.T) {
+	queue := newPriorityQueue(0)
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 1, Priority: 1}})
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 2, Priority: 5}})
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 3, Priority: 5}})
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 4, Priority: 3}})
+
+	var order []int64
+	for queue.Len() > 0 {
+		order = append(order, queue.pop().workRequest.StepID)
+	}
+	assert.Equal(t, []int64{2, 3, 4, 1}, order)
+}
+
+func TestPriorityQueue_AgingPromotesOldTasks(t *testing.T) {
+	queue := newPriorityQueue(1000)
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 1, Priority: 1}})
+	// Simulate the low-priority task having waited for five seconds.
+	queue.items[0].enqueuedAt -= 5000
+	queue.push(stepTask{workRequest: common.WorkRequest{StepID: 2, Priority: 3}})
+
+	assert.Equal(t, int64(1), queue.pop().workRequest.StepID)
+	assert.Equal(t, int64(2), queue.pop().workRequest.StepID)
+}
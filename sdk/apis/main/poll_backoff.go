@@ -0,0 +1,62 @@
+package apis
+
+import (
+	"math/rand"
+	"time"
+)
+
+const minPollBackoffMillis = 10
+
+// pollBackoff computes the delay before the next poll. Every poll that comes
+// back empty multiplies the delay, up to maxMillis, and subtracts a random
+// jitter so idle clients do not poll in lockstep. Receiving work resets the
+// delay to baseMillis.
+type pollBackoff struct {
+	baseMillis             int64
+	maxMillis              int64
+	jitterUpperBoundMillis int64
+	multiplier             int64
+	currentMillis          int64
+}
+
+func newPollBackoff(baseMillis, maxMillis, jitterUpperBoundMillis int64, multiplier int64) *pollBackoff {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	return &pollBackoff{
+		baseMillis:             baseMillis,
+		maxMillis:              maxMillis,
+		jitterUpperBoundMillis: jitterUpperBoundMillis,
+		multiplier:             multiplier,
+		currentMillis:          baseMillis,
+	}
+}
+
+func (b *pollBackoff) reset() time.Duration {
+	b.currentMillis = b.baseMillis
+	return time.Duration(b.currentMillis) * time.Millisecond
+}
+
+func (b *pollBackoff) onEmptyPoll() time.Duration {
+	if b.maxMillis <= b.baseMillis {
+		return b.reset()
+	}
+	next := b.currentMillis
+	if next < minPollBackoffMillis {
+		next = minPollBackoffMillis
+	}
+	next *= b.multiplier
+	if next > b.maxMillis {
+		next = b.maxMillis
+	}
+	b.currentMillis = next
+
+	jitterBound := next / 2
+	if jitterBound > b.jitterUpperBoundMillis {
+		jitterBound = b.jitterUpperBoundMillis
+	}
+	if jitterBound > 0 {
+		next -= rand.Int63n(jitterBound + 1)
+	}
+	return time.Duration(next) * time.Millisecond
+}
@@ -0,0 +1,54 @@
+package apis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollBackoff_GrowsOnEmptyPollsAndResets(t *testing.T) {
+	backoff := newPollBackoff(100, 1000, 0, 2)
+
+	assert.Equal(t, 200*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 400*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 800*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 1000*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 1000*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 100*time.Millisecond, backoff.reset())
+	assert.Equal(t, 200*time.Millisecond, backoff.onEmptyPoll())
+}
+
+func TestPollBackoff_JitterStaysWithinBounds(t *testing.T) {
+	backoff := newPollBackoff(100, 1000, 50, 2)
+	for i := 0; i < 20; i++ {
+		delay := backoff.onEmptyPoll()
+		expected := time.Duration(backoff.currentMillis) * time.Millisecond
+		assert.LessOrEqual(t, delay, expected)
+		assert.GreaterOrEqual(t, delay, expected-50*time.Millisecond)
+	}
+}
+
+func TestPollBackoff_DisabledWhenMaxNotAboveBase(t *testing.T) {
+	backoff := newPollBackoff(100, 100, 50, 2)
+	assert.Equal(t, 100*time.Millisecond, backoff.onEmptyPoll())
+	assert.Equal(t, 100*time.Millisecond, backoff.onEmptyPoll())
+}
+
+func TestSleepUnlessStopped_WakesUpOnStop(t *testing.T) {
+	uc := &UnmeshedClient{stopSignal: make(chan struct{})}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		uc.signalStop()
+	}()
+
+	start := time.Now()
+	uc.sleepUnlessStopped(30 * time.Second)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.True(t, uc.stopPolling.Load())
+
+	// Once stopped, sleeping returns right away.
+	start = time.Now()
+	uc.sleepUnlessStopped(30 * time.Second)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,64 @@
+package apis
+
+import (
+	"container/heap"
+	"time"
+)
+
+type queuedTask struct {
+	task       stepTask
+	enqueuedAt int64
+	seq        uint64
+}
+
+// priorityQueue orders tasks by their work request priority, higher values
+// first. With aging, every agingMillis a task spends in the queue raises its
+// effective priority by one, so low-priority work cannot starve. Since all
+// tasks age at the same rate, the ordering can be computed from the enqueue
+// time and does not change while tasks wait.
+type priorityQueue struct {
+	items       []*queuedTask
+	agingMillis int64
+	nextSeq     uint64
+}
+
+func newPriorityQueue(agingMillis int64) *priorityQueue {
+	return &priorityQueue{agingMillis: agingMillis}
+}
+
+func (q *priorityQueue) Len() int { return len(q.items) }
+
+func (q *priorityQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.agingMillis > 0 {
+		scoreA := a.task.workRequest.GetPriority()*q.agingMillis - a.enqueuedAt
+		scoreB := b.task.workRequest.GetPriority()*q.agingMillis - b.enqueuedAt
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+	} else if a.task.workRequest.GetPriority() != b.task.workRequest.GetPriority() {
+		return a.task.workRequest.GetPriority() > b.task.workRequest.GetPriority()
+	}
+	return a.seq < b.seq
+}
+
+func (q *priorityQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *priorityQueue) Push(x any) { q.items = append(q.items, x.(*queuedTask)) }
+
+func (q *priorityQueue) Pop() any {
+	last := len(q.items) - 1
+	item := q.items[last]
+	q.items[last] = nil
+	q.items = q.items[:last]
+	return item
+}
+
+func (q *priorityQueue) push(task stepTask) {
+	q.nextSeq++
+	heap.Push(q, &queuedTask{task: task, enqueuedAt: time.Now().UnixMilli(), seq: q.nextSeq})
+}
+
+func (q *priorityQueue) pop() stepTask {
+	return heap.Pop(q).(*queuedTask).task
+}
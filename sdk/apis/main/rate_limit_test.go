@@ -0,0 +1,42 @@
+package apis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apisHttp "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/http"
+	register "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/register"
+	workersApi "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
+)
+
+func TestAcquirePermits_UsesRateLimitSetAfterRegistration(t *testing.T) {
+	config := configs.NewClientConfig()
+	uc := &UnmeshedClient{
+		ClientConfig: config,
+		registrationClient: register.NewRegistrationClient(config,
+			apisHttp.NewHttpClientFactory(config), apisHttp.NewHttpRequestFactory(config)),
+		workerByID:     make(map[string]bool),
+		workersByID:    make(map[string]*workersApi.Worker),
+		pollStates:     make(map[string]*common.StepPollState),
+		dedicatedPools: make(map[string]*executionPool),
+		pausedWorkers:  make(map[string]bool),
+	}
+	worker := workersApi.NewWorker(func() {}, "limited")
+	worker.SetMaxInProgress(10)
+	assert.NoError(t, uc.registerWorker(worker))
+	worker.SetRateLimit(2, time.Hour, 2)
+	workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+
+	_, _, requested, err := uc.acquirePermits()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requested[workerId])
+	assert.Equal(t, 0, worker.GetRateLimiter().Available())
+
+	// Unused tokens go back to the same bucket they were taken from.
+	uc.releaseUnusedPermits(map[string]int{workerId: 0}, requested)
+	assert.Equal(t, 2, worker.GetRateLimiter().Available())
+	assert.Equal(t, 10, uc.pollStates[workerId].MaxAvailable())
+}
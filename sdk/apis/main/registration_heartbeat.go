@@ -0,0 +1,89 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	poller "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/poller"
+	register "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/register"
+)
+
+// startRegistrationHeartbeat renews the worker registration on the configured
+// interval, and right away when the workers change or a poll shows the server
+// lost track of this client, until the client stops. Requests that arrive while
+// a renewal is in progress are coalesced into a single follow-up renewal.
+func (uc *UnmeshedClient) startRegistrationHeartbeat() {
+	ctx, cancel := uc.stopContext()
+	defer cancel()
+
+	var tick <-chan time.Time
+	if interval := uc.ClientConfig.GetRegistrationRenewIntervalSecs(); interval > 0 {
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-uc.done:
+			return
+		case <-tick:
+			if _, err := uc.registrationClient.RenewRegistrationOnce(); err != nil {
+				log.Printf("Error renewing registration: %v", err)
+			}
+		case <-uc.reregister:
+			if _, err := uc.registrationClient.RenewRegistrationContext(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Error renewing registration: %v", err)
+			}
+		}
+	}
+}
+
+// renewRegistrationSoon asks the heartbeat to renew the registration now. It
+// never blocks; a renewal that is already pending covers this request too.
+func (uc *UnmeshedClient) renewRegistrationSoon() {
+	select {
+	case uc.reregister <- struct{}{}:
+	default:
+	}
+}
+
+// stopContext returns a context that is cancelled as soon as the client starts
+// stopping, including during a graceful Shutdown.
+func (uc *UnmeshedClient) stopContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(uc.rootCtx)
+	go func() {
+		select {
+		case <-uc.stopSignal:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// requestReregistration asks the heartbeat to renew the registration when a
+// poll is rejected with a status code indicating an unknown client or worker.
+func (uc *UnmeshedClient) requestReregistration(err error) {
+	var pollErr *poller.PollError
+	if !errors.As(err, &pollErr) {
+		return
+	}
+	for _, statusCode := range uc.ClientConfig.ReregistrationStatusCodes() {
+		if pollErr.StatusCode == statusCode {
+			log.Printf("Server does not recognise this client (status %d), renewing registration", statusCode)
+			uc.renewRegistrationSoon()
+			return
+		}
+	}
+}
+
+// GetRegistrationStatus reports whether the workers are registered with the
+// server and when the registration was last renewed.
+func (uc *UnmeshedClient) GetRegistrationStatus() register.RegistrationStatus {
+	return uc.registrationClient.GetRegistrationStatus()
+}
@@ -0,0 +1,55 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	poller "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/poller"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
+)
+
+func TestRequestReregistration_OnUnknownClient(t *testing.T) {
+	uc := &UnmeshedClient{
+		ClientConfig: configs.NewClientConfig(),
+		reregister:   make(chan struct{}, 1),
+	}
+
+	uc.requestReregistration(errors.New("connection refused"))
+	uc.requestReregistration(fmt.Errorf("failed to poll: %w", &poller.PollError{StatusCode: 500, Body: "internal error"}))
+	uc.requestReregistration(&poller.PollError{StatusCode: 400, Body: "worker not registered"})
+	assert.Len(t, uc.reregister, 0)
+
+	uc.requestReregistration(fmt.Errorf("failed to poll: %w", &poller.PollError{StatusCode: 404}))
+	uc.requestReregistration(&poller.PollError{StatusCode: 410, Body: "gone"})
+	assert.Len(t, uc.reregister, 1)
+}
+
+func TestRenewRegistrationInBackground_CoalescesRequests(t *testing.T) {
+	uc := &UnmeshedClient{reregister: make(chan struct{}, 1)}
+
+	uc.renewRegistrationInBackground()
+	assert.Len(t, uc.reregister, 0, "nothing to renew before the client started")
+
+	uc.processingStarted.Store(true)
+	uc.renewRegistrationInBackground()
+	uc.renewRegistrationInBackground()
+	uc.renewRegistrationInBackground()
+	assert.Len(t, uc.reregister, 1)
+}
+
+func TestStopContext_CancelledOnStop(t *testing.T) {
+	uc := &UnmeshedClient{rootCtx: context.Background(), stopSignal: make(chan struct{})}
+	ctx, cancel := uc.stopContext()
+	defer cancel()
+
+	uc.signalStop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled when the client stopped")
+	}
+}
@@ -0,0 +1,51 @@
+package apis
+
+// WorkerStats describes the polling state of a single worker.
+type WorkerStats struct {
+	Namespace string
+	Name      string
+	Available int
+	Total     int
+	Paused    bool
+}
+
+// RuntimeStats is a point in time view of the client's activity.
+type RuntimeStats struct {
+	Executing      int
+	PendingSubmits int
+	DuplicatesSeen int64
+	Workers        []WorkerStats
+	DeferredSteps  []DeferredStep
+}
+
+// GetRuntimeStats returns the number of steps being executed and waiting to be
+// submitted, the duplicate deliveries skipped so far, the permits and paused
+// state of each worker, and the deferred steps not yet finished through this
+// client.
+func (uc *UnmeshedClient) GetRuntimeStats() RuntimeStats {
+	stats := RuntimeStats{
+		Executing:      int(uc.executingCount.Load()),
+		DuplicatesSeen: uc.duplicatesSeen.Load(),
+		DeferredSteps:  uc.pendingDeferredSteps(),
+	}
+	if uc.submitClient != nil {
+		stats.PendingSubmits = uc.submitClient.GetSubmitTrackerSize()
+	}
+
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
+	for _, worker := range uc.Workers {
+		workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+		workerStats := WorkerStats{
+			Namespace: worker.GetNamespace(),
+			Name:      worker.GetName(),
+			Paused:    uc.pausedWorkers[workerId],
+		}
+		if state := uc.pollStates[workerId]; state != nil {
+			workerStats.Available = state.MaxAvailable()
+			workerStats.Total = state.GetTotalCount()
+		}
+		stats.Workers = append(stats.Workers, workerStats)
+	}
+	return stats
+}
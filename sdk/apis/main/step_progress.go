@@ -0,0 +1,38 @@
+package apis
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+// stepProgress submits the progress updates of a running step as RUNNING
+// responses. Once the step has finished no more updates are accepted, and the
+// submit client drops queued updates once the step's result is queued, so an
+// update can never reach the server after the final result.
+type stepProgress struct {
+	uc          *UnmeshedClient
+	workRequest *common.WorkRequest
+	lock        sync.Mutex
+	finished    bool
+}
+
+func (p *stepProgress) report(partialOutput interface{}) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.finished {
+		return errors.New("step has already finished")
+	}
+	if p.uc.submitClient == nil {
+		return errors.New("results submission is disabled")
+	}
+	workResponse := p.uc.workResponseBuilder.RunningResponse(p.workRequest, common.NewStepResult(partialOutput))
+	return p.uc.submitClient.SubmitProgress(workResponse)
+}
+
+func (p *stepProgress) finish() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.finished = true
+}
@@ -0,0 +1,22 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestStepProgress_RejectsUpdatesAfterFinish(t *testing.T) {
+	progress := &stepProgress{
+		uc:          &UnmeshedClient{workResponseBuilder: common.NewWorkResponseBuilder()},
+		workRequest: common.NewWorkRequest(),
+	}
+	stepContext := common.NewStepContext(progress.workRequest)
+	stepContext.SetProgressReporter(progress.report)
+
+	assert.EqualError(t, stepContext.ReportProgress(map[string]interface{}{"done": 1}), "results submission is disabled")
+
+	progress.finish()
+	assert.EqualError(t, stepContext.ReportProgress(map[string]interface{}{"done": 2}), "step has already finished")
+}
@@ -0,0 +1,59 @@
+package apis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	workerRunner "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/runner"
+	workersApi "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
+)
+
+func TestRunStep_TimedOutStepReturnsWithoutWaitingForWorker(t *testing.T) {
+	uc := &UnmeshedClient{
+		ClientConfig:        configs.NewClientConfig(),
+		workResponseBuilder: common.NewWorkResponseBuilder(),
+		workerRunner:        workerRunner.NewWorkerRunner(),
+		rootCtx:             context.Background(),
+	}
+	release := make(chan struct{})
+	defer close(release)
+	worker := workersApi.NewWorker(func(in map[string]interface{}) map[string]interface{} {
+		// Ignores its context, like a worker stuck in a blocking call.
+		<-release
+		return in
+	}, "stuck")
+	worker.SetStepTimeoutMillis(20)
+
+	state := common.NewStepPollState(1)
+	assert.Equal(t, 1, state.AcquireMaxAvailable())
+	uc.executingCount.Store(1)
+
+	finished := make(chan struct{})
+	go func() {
+		uc.runStep(worker, state, common.NewWorkRequest())
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runStep waited for a worker that ignores its context")
+	}
+	assert.Equal(t, int32(0), uc.executingCount.Load())
+}
+
+func TestStepTimeout_DefaultsToConfig(t *testing.T) {
+	uc := &UnmeshedClient{ClientConfig: configs.NewClientConfig()}
+	worker := workersApi.NewWorker(func() {}, "default-timeout")
+	assert.Equal(t, 5*time.Second, uc.stepTimeout(worker))
+
+	worker.SetStepTimeoutMillis(100)
+	assert.Equal(t, 100*time.Millisecond, uc.stepTimeout(worker))
+
+	uc.ClientConfig.SetStepTimeoutMillis(0)
+	assert.Equal(t, time.Duration(0), uc.stepTimeout(workersApi.NewWorker(func() {}, "no-timeout")))
+}
@@ -1,6 +1,9 @@
 package apis
 
 import (
+	"container/list"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -40,11 +43,22 @@ func getGID() uint64 {
 	return id
 }
 
+// Deprecated: the SDK tracks the current step through the context passed to
+// workers; use common.StepContextFrom instead.
 func (uc *UnmeshedClient) SetCurrentWorkRequest(workRequest *common.WorkRequest) {
 	gid := getGID()
 	goroutineWorkRequestMap.Store(gid, workRequest)
 }
 
+func (uc *UnmeshedClient) clearCurrentWorkRequest() {
+	goroutineWorkRequestMap.Delete(getGID())
+}
+
+// GetCurrentWorkRequest returns the work request executed by the calling
+// goroutine. It only works on the goroutine the SDK invoked the worker on.
+//
+// Deprecated: accept a context.Context in the worker and use
+// common.StepContextFrom, which also works from goroutines the worker starts.
 func (uc *UnmeshedClient) GetCurrentWorkRequest() *common.WorkRequest {
 	gid := getGID()
 	if v, ok := goroutineWorkRequestMap.Load(gid); ok {
@@ -82,6 +96,10 @@ func setupLogging() {
 	}
 }
 
+// PanicHandler is notified when a worker panics while executing a step, e.g.
+// to forward the crash to an error reporting service.
+type PanicHandler func(workRequest *common.WorkRequest, panicErr *common.PanicError)
+
 type UnmeshedClient struct {
 	ClientConfig             *configs.ClientConfig
 	Workers                  []workersApi.Worker
@@ -97,6 +115,12 @@ type UnmeshedClient struct {
 	retryCount               atomic.Int32
 	workerByID               map[string]bool               // Changed from workerByName to workerByID
 	workersByID              map[string]*workersApi.Worker // Changed from workersByName to workersByID
+	dedicatedPools           map[string]*executionPool
+	pausedWorkers            map[string]bool
+	sharedPool               *executionPool
+	allPools                 []*executionPool
+	poolsClosed              bool
+	workersLock              sync.RWMutex
 	httpClientFactory        *apis.HttpClientFactory
 	httpRequestFactory       *apis.HttpRequestFactory
 	registrationClient       *register.RegistrationClient
@@ -106,12 +130,25 @@ type UnmeshedClient struct {
 	workResponseBuilder      *common.WorkResponseBuilder
 	workerRunner             *workerRunner.WorkerRunner
 	stopPolling              atomic.Bool
+	stopSignal               chan struct{}
 	done                     chan struct{}
+	rootCtx                  context.Context
+	cancelRootCtx            context.CancelFunc
+	panicHandler             PanicHandler
+	processingStarted        atomic.Bool
+	workerPoolDone           chan struct{}
+	reregister               chan struct{}
+	dedup                    *dedupCache
+	duplicatesSeen           atomic.Int64
+	deferredLock             sync.Mutex
+	deferredSteps            map[common.CompletionToken]*list.Element
+	deferredOrder            *list.List
 
 	lastPrintedPolling int64
 	lastPrintedRunning int64
 
-	stopOnce sync.Once
+	stopOnce       sync.Once
+	stopSignalOnce sync.Once
 }
 
 func NewUnmeshedClient(
@@ -132,6 +169,13 @@ func NewUnmeshedClient(
 	submitClient := submit.NewSubmitClient(httpRequestFactory, clientConfig)
 	processClient := process.NewProcessClient(httpClientFactory, httpRequestFactory, clientConfig)
 
+	rootCtx, cancelRootCtx := context.WithCancel(context.Background())
+
+	var dedup *dedupCache
+	if clientConfig.GetDedupCacheSize() > 0 && clientConfig.GetDedupTTLSeconds() > 0 {
+		dedup = newDedupCache(clientConfig.GetDedupCacheSize(), time.Duration(clientConfig.GetDedupTTLSeconds())*time.Second)
+	}
+
 	unmeshedClient := &UnmeshedClient{
 		ClientConfig:             clientConfig,
 		Workers:                  []workersApi.Worker{},
@@ -147,6 +191,8 @@ func NewUnmeshedClient(
 		retryCount:               atomic.Int32{},
 		workerByID:               make(map[string]bool),               // Changed
 		workersByID:              make(map[string]*workersApi.Worker), // Changed
+		dedicatedPools:           make(map[string]*executionPool),
+		pausedWorkers:            make(map[string]bool),
 		httpClientFactory:        httpClientFactory,
 		httpRequestFactory:       httpRequestFactory,
 		registrationClient:       register.NewRegistrationClient(clientConfig, httpClientFactory, httpRequestFactory),
@@ -156,7 +202,13 @@ func NewUnmeshedClient(
 		workResponseBuilder:      common.NewWorkResponseBuilder(),
 		workerRunner:             workerRunner.NewWorkerRunner(),
 		stopPolling:              atomic.Bool{},
+		stopSignal:               make(chan struct{}),
 		done:                     make(chan struct{}),
+		rootCtx:                  rootCtx,
+		cancelRootCtx:            cancelRootCtx,
+		workerPoolDone:           make(chan struct{}),
+		reregister:               make(chan struct{}, 1),
+		dedup:                    dedup,
 		lastPrintedPolling:       0,
 		lastPrintedRunning:       0,
 	}
@@ -165,6 +217,8 @@ func NewUnmeshedClient(
 }
 
 func (uc *UnmeshedClient) getWorkers() []workersApi.Worker {
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
 	return uc.Workers
 }
 
@@ -172,30 +226,12 @@ func formattedWorkerID(namespace string, name string) string {
 	return fmt.Sprintf("%s:-#-:%s", namespace, name)
 }
 
-func (uc *UnmeshedClient) pollForWork() ([]common.WorkRequest, error) {
-
-	registeredWorkers := uc.registrationClient.GetWorkers()
-	var workerTasks []common.StepSize
-	workerRequestCount := make(map[string]int)
-
-	for _, worker := range registeredWorkers {
-		stepQueueNameData := common.StepQueueNameData{
-			OrgId:       0,
-			Namespace:   worker.GetNamespace(),
-			StepType:    "WORKER",
-			Name:        worker.GetName(),
-		}
-		workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
-		state, exists := uc.pollStates[workerId]
-		if !exists {
-			return nil, fmt.Errorf("unexpected missing poll state for worker: %s", workerId)
-		}
-		size := state.AcquireMaxAvailable()
-		workerRequestCount[workerId] = size
-		if size > 0 {
-			workerTask := common.NewStepSize(stepQueueNameData, size)
-			workerTasks = append(workerTasks, workerTask)
-		}
+// pollForWork polls for as much work as there are free permits. The returned
+// flag is false when no permits were available and the server was not asked.
+func (uc *UnmeshedClient) pollForWork() ([]common.WorkRequest, bool, error) {
+	registeredWorkers, workerTasks, workerRequestCount, err := uc.acquirePermits()
+	if err != nil {
+		return nil, false, err
 	}
 
 	now := time.Now().Unix()
@@ -205,13 +241,13 @@ func (uc *UnmeshedClient) pollForWork() ([]common.WorkRequest, error) {
 	}
 
 	if len(workerTasks) == 0 {
-		return nil, nil
+		return nil, false, nil
 	}
 
 	workRequests, err := uc.pollerClient.Poll(workerTasks)
 	if err != nil {
 		uc.releaseUnusedPermits(make(map[string]int), workerRequestCount)
-		return nil, fmt.Errorf("failed to poll work requests: %w", err)
+		return nil, true, fmt.Errorf("failed to poll work requests: %w", err)
 	}
 
 	if len(workRequests) > 0 {
@@ -230,12 +266,18 @@ func (uc *UnmeshedClient) pollForWork() ([]common.WorkRequest, error) {
 		logEntries := make([]string, 0, len(registeredWorkers))
 		for _, s := range registeredWorkers {
 			workerId := formattedWorkerID(s.GetNamespace(), s.GetName())
-			pollState := uc.pollStates[workerId]
+			pollState := uc.pollStateFor(workerId)
+			if pollState == nil {
+				continue
+			}
 			available := pollState.MaxAvailable()
 			total := pollState.GetTotalCount()
 			requested := workerRequestCount[workerId]
-			logEntries = append(logEntries,
-				fmt.Sprintf("%s:%s = Available[%d] / [%d] / [%d]", s.GetNamespace(), s.GetName(), available, requested, total))
+			entry := fmt.Sprintf("%s:%s = Available[%d] / [%d] / [%d]", s.GetNamespace(), s.GetName(), available, requested, total)
+			if uc.IsWorkerPaused(s.GetNamespace(), s.GetName()) {
+				entry += " (paused)"
+			}
+			logEntries = append(logEntries, entry)
 		}
 		logStr := strings.Join(logEntries, ", ")
 		executingCount := uc.executingCount.Load()
@@ -243,42 +285,188 @@ func (uc *UnmeshedClient) pollForWork() ([]common.WorkRequest, error) {
 		if uc.submitClient != nil {
 			submitTrackerSize = int32(uc.submitClient.GetSubmitTrackerSize())
 		}
-		log.Printf("Running : %d st: %d t: %d - permits %s", executingCount, submitTrackerSize, executingCount+submitTrackerSize, logStr)
+		deferredCount := uc.deferredStepCount()
+		log.Printf("Running : %d st: %d t: %d deferred: %d - permits %s", executingCount, submitTrackerSize, executingCount+submitTrackerSize, deferredCount, logStr)
 		uc.lastPrintedRunning = now
 	}
 
-	return workRequests, nil
+	return workRequests, true, nil
 }
 
-func (uc *UnmeshedClient) runStep(worker *workersApi.Worker, workRequest *common.WorkRequest) {
-	uc.SetCurrentWorkRequest(workRequest)
+// acquirePermits takes the free permits of every registered worker and turns
+// them into the step sizes to poll for.
+func (uc *UnmeshedClient) acquirePermits() ([]workersApi.Worker, []common.StepSize, map[string]int, error) {
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
 
-	result, err := uc.workerRunner.RunWorker(worker, workRequest)
+	registeredWorkers := uc.registrationClient.GetWorkers()
+	var workerTasks []common.StepSize
+	workerRequestCount := make(map[string]int)
 
-	var stepResult *common.StepResult
+	for _, worker := range registeredWorkers {
+		stepQueueNameData := common.StepQueueNameData{
+			OrgId:     0,
+			Namespace: worker.GetNamespace(),
+			StepType:  "WORKER",
+			Name:      worker.GetName(),
+		}
+		workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+		if uc.pausedWorkers[workerId] {
+			continue
+		}
+		state, exists := uc.pollStates[workerId]
+		if !exists {
+			uc.releaseUnusedPermitsLocked(make(map[string]int), workerRequestCount)
+			return nil, nil, nil, fmt.Errorf("unexpected missing poll state for worker: %s", workerId)
+		}
+		var size int
+		if pool := uc.dedicatedPools[workerId]; pool != nil {
+			size = state.AcquireUpTo(pool.freeCapacity())
+		} else {
+			size = state.AcquireMaxAvailable()
+		}
+		if rateLimiter := uc.rateLimiterLocked(workerId); rateLimiter != nil && size > 0 {
+			allowed := rateLimiter.TryAcquire(size)
+			state.Release(size - allowed)
+			size = allowed
+		}
+		workerRequestCount[workerId] = size
+		if size > 0 {
+			workerTask := common.NewStepSize(stepQueueNameData, size)
+			workerTasks = append(workerTasks, workerTask)
+		}
+	}
+	return registeredWorkers, workerTasks, workerRequestCount, nil
+}
 
-	if sr, ok := result.(*common.StepResult); ok {
-		stepResult = sr
-	} else {
-		if result == nil {
-			result = map[string]interface{}{}
+// rateLimiterLocked returns the rate limiter of a registered worker, or nil when
+// it has none. Tokens are always taken from and returned to the worker the
+// caller registered, not the copy held by the registration client. The caller
+// holds workersLock.
+func (uc *UnmeshedClient) rateLimiterLocked(workerId string) *common.TokenBucket {
+	worker := uc.workersByID[workerId]
+	if worker == nil {
+		return nil
+	}
+	return worker.GetRateLimiter()
+}
+
+func (uc *UnmeshedClient) pollStateFor(workerId string) *common.StepPollState {
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
+	return uc.pollStates[workerId]
+}
+
+// stepTimeout returns the time a worker may spend on a single step, preferring
+// the worker's own setting over the client configuration. Zero means no limit.
+func (uc *UnmeshedClient) stepTimeout(worker *workersApi.Worker) time.Duration {
+	stepTimeoutMillis := worker.GetStepTimeoutMillis()
+	if stepTimeoutMillis <= 0 {
+		stepTimeoutMillis = uc.ClientConfig.GetStepTimeoutMillis()
+	}
+	return time.Duration(stepTimeoutMillis) * time.Millisecond
+}
+
+// newStepContext derives the context handed to a worker execution. It is
+// cancelled when the client stops or when the step timeout expires.
+func (uc *UnmeshedClient) newStepContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(uc.rootCtx, timeout)
+	}
+	return context.WithCancel(uc.rootCtx)
+}
+
+type stepOutcome struct {
+	stepResult *common.StepResult
+	err        error
+}
+
+func (uc *UnmeshedClient) runStep(worker *workersApi.Worker, state *common.StepPollState, workRequest *common.WorkRequest) {
+	timeout := uc.stepTimeout(worker)
+	ctx, cancel := uc.newStepContext(timeout)
+	defer cancel()
+
+	progress := &stepProgress{uc: uc, workRequest: workRequest}
+	stepContext := common.NewStepContext(workRequest)
+	stepContext.SetProgressReporter(progress.report)
+	ctx = common.WithStepContext(ctx, stepContext)
+
+	outcomes := make(chan stepOutcome, 1)
+	go func() {
+		uc.SetCurrentWorkRequest(workRequest)
+		defer uc.clearCurrentWorkRequest()
+		stepResult, err := uc.workerRunner.RunWorker(ctx, worker, workRequest)
+		outcomes <- stepOutcome{stepResult: stepResult, err: err}
+	}()
+
+	var outcome stepOutcome
+	select {
+	case outcome = <-outcomes:
+	case <-ctx.Done():
+		select {
+		case outcome = <-outcomes:
+		default:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				progress.finish()
+				log.Printf("Step %d of worker %s:%s timed out after %v",
+					workRequest.GetStepID(), workRequest.GetStepNamespace(), workRequest.GetStepName(), timeout)
+				// The worker keeps its goroutine but its result is discarded; the
+				// cancelled context tells it to give up. The step's permit is
+				// released once the TIMED_OUT result is submitted.
+				uc.submitWorkResponse(state, uc.workResponseBuilder.TimedOutResponse(workRequest, timeout))
+				return
+			}
+			outcome = <-outcomes
 		}
-		stepResult = common.NewStepResult(result)
+	}
+	progress.finish()
+	stepResult, err := outcome.stepResult, outcome.err
+
+	var panicErr *common.PanicError
+	if errors.As(err, &panicErr) {
+		log.Printf("Worker %s:%s panicked on step %d: %v\n%s",
+			workRequest.GetStepNamespace(), workRequest.GetStepName(), workRequest.GetStepID(), panicErr.Value, panicErr.Stack)
+		uc.notifyPanicHandler(workRequest, panicErr)
+	}
+
+	if stepResult == nil {
+		stepResult = common.NewStepResult(map[string]interface{}{})
 	}
 
 	if err != nil {
-		uc.handleWorkCompletion(workRequest, stepResult, &err)
+		uc.handleWorkCompletion(state, workRequest, stepResult, &err)
 	} else {
-		uc.handleWorkCompletion(workRequest, stepResult, nil)
+		uc.handleWorkCompletion(state, workRequest, stepResult, nil)
 	}
 }
 
-func (uc *UnmeshedClient) handleWorkCompletion(workRequest *common.WorkRequest, stepResult *common.StepResult, throwable *error) {
-	stepId := formattedWorkerID(workRequest.GetStepNamespace(), workRequest.GetStepName())
-	state := uc.pollStates[stepId]
+func (uc *UnmeshedClient) notifyPanicHandler(workRequest *common.WorkRequest, panicErr *common.PanicError) {
+	if uc.panicHandler == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic handler failed: %v", r)
+		}
+	}()
+	uc.panicHandler(workRequest, panicErr)
+}
 
+func (uc *UnmeshedClient) handleWorkCompletion(state *common.StepPollState, workRequest *common.WorkRequest, stepResult *common.StepResult, throwable *error) {
 	var workResponse *common.WorkResponse
 
+	if throwable == nil && stepResult.Deferred {
+		log.Printf("Step %d of process %d deferred, waiting for it to be completed externally",
+			workRequest.GetStepID(), workRequest.GetProcessID())
+		uc.stepDeferred(workRequest)
+		// Nothing is submitted for a deferred step, so there is no
+		// acknowledgement to wait for before a redelivery may run again.
+		uc.forgetDelivery(workRequest.GetStepExecutionID())
+		state.Release(1)
+		uc.executingCount.Add(-1)
+		return
+	}
+
 	if throwable != nil {
 		workResponse = uc.workResponseBuilder.FailResponse(workRequest, *throwable)
 	} else if stepResult.KeepRunning && stepResult.RescheduleAfterSeconds > 0 {
@@ -287,19 +475,46 @@ func (uc *UnmeshedClient) handleWorkCompletion(workRequest *common.WorkRequest,
 		workResponse = uc.workResponseBuilder.SuccessResponse(workRequest, stepResult)
 	}
 
+	uc.submitWorkResponse(state, workResponse)
+}
+
+// submitWorkResponse queues the result of a step. The permit held in state is
+// released, and a finished step execution is dropped from the dedup cache, once
+// the result has been accepted by the server.
+func (uc *UnmeshedClient) submitWorkResponse(state *common.StepPollState, workResponse *common.WorkResponse) {
+	stepExecutionID := workResponse.GetStepExecutionID()
+	var onResolved func()
+	if workResponse.GetStatus() == common.StepStatusRunning {
+		// A rescheduled step is delivered again on purpose.
+		uc.forgetDelivery(stepExecutionID)
+	} else {
+		uc.deliveryFinished(stepExecutionID)
+		onResolved = func() { uc.forgetDelivery(stepExecutionID) }
+	}
 	if uc.submitClient != nil {
-		uc.submitClient.Submit(workResponse, state)
+		uc.submitClient.SubmitWithCallback(workResponse, state, onResolved)
+	} else if onResolved != nil {
+		onResolved()
 	}
 	uc.executingCount.Add(-1)
 }
 
 func (uc *UnmeshedClient) releaseUnusedPermits(workerReceivedCount, workerRequestCount map[string]int) {
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
+	uc.releaseUnusedPermitsLocked(workerReceivedCount, workerRequestCount)
+}
+
+func (uc *UnmeshedClient) releaseUnusedPermitsLocked(workerReceivedCount, workerRequestCount map[string]int) {
 	for workerID, requestedCount := range workerRequestCount {
 		pollState, exists := uc.pollStates[workerID]
 
 		if exists {
 			receivedCount := workerReceivedCount[workerID]
 			pollState.Release(requestedCount - receivedCount)
+			if rateLimiter := uc.rateLimiterLocked(workerID); rateLimiter != nil {
+				rateLimiter.Return(requestedCount - receivedCount)
+			}
 		}
 	}
 }
@@ -309,42 +524,30 @@ func (uc *UnmeshedClient) startAsyncTaskProcessing() {
 	const minBackoff = 100 * time.Millisecond
 	const maxBackoff = 30 * time.Second
 
+	uc.processingStarted.Store(true)
+
 	// Determine worker pool size
 	workerCount := int(uc.ClientConfig.GetMaxWorkers())
 	if workerCount < 10 {
 		workerCount = 10
 	}
-	workQueue := make(chan common.WorkRequest, workerCount*2)
-
-	// Start worker pool
-	var workerWg sync.WaitGroup
-	for i := 0; i < workerCount; i++ {
-		workerWg.Add(1)
-		go func() {
-			defer workerWg.Done()
-			for workRequest := range workQueue {
-				// Use worker ID (namespace:name) to look up the worker
-				workerId := formattedWorkerID(workRequest.GetStepNamespace(), workRequest.GetStepName())
-				foundWorker := uc.workersByID[workerId]
-
-				if foundWorker != nil {
-					uc.runStep(foundWorker, &workRequest)
-				} else {
-					log.Printf("No worker found for step '%s:%s'\n", workRequest.GetStepNamespace(), workRequest.GetStepName())
-				}
-			}
-		}()
+	uc.workersLock.Lock()
+	uc.sharedPool = newExecutionPool("shared", workerCount, workerCount*2, uc.ClientConfig.GetPriorityAgingMillis(), uc.runTask)
+	uc.allPools = append(uc.allPools, uc.sharedPool)
+	for workerId, worker := range uc.workersByID {
+		uc.startDedicatedPoolLocked(workerId, worker)
 	}
+	uc.workersLock.Unlock()
 
 	go func() {
 		var (
 			lastLogTime    = time.Now()
 			pollRetryCount = 1
+			pollDelay      = uc.newPollBackoff()
 		)
 
 		for !uc.stopPolling.Load() {
-			pollInterval := time.Duration(uc.ClientConfig.GetDelayMillis()) * time.Millisecond
-			workRequests, err := uc.pollForWork()
+			workRequests, polled, err := uc.pollForWork()
 
 			if err != nil {
 				backoff := minBackoff << (pollRetryCount - 1)
@@ -352,36 +555,168 @@ func (uc *UnmeshedClient) startAsyncTaskProcessing() {
 					backoff = maxBackoff
 				}
 				log.Printf("Polling error: %v, will retry after %v", err, backoff)
+				uc.requestReregistration(err)
 				pollRetryCount++
-				time.Sleep(backoff)
+				uc.sleepUnlessStopped(backoff)
 				continue
 			} else {
 				pollRetryCount = 1
 			}
 
-			if len(workRequests) > 0 {
-				for _, workRequest := range workRequests {
-					workQueue <- workRequest
-				}
+			for _, workRequest := range workRequests {
+				uc.dispatch(workRequest)
+			}
+
+			var pollInterval time.Duration
+			if polled && len(workRequests) == 0 {
+				pollInterval = pollDelay.onEmptyPoll()
+			} else {
+				pollInterval = pollDelay.reset()
 			}
 
 			if time.Since(lastLogTime) >= logInterval {
-				log.Printf("Poll interval is %d ms", uc.ClientConfig.GetDelayMillis())
+				log.Printf("Poll interval is %d ms", pollInterval.Milliseconds())
 				lastLogTime = time.Now()
 			}
 
-			time.Sleep(pollInterval)
+			uc.sleepUnlessStopped(pollInterval)
+		}
+		uc.workersLock.Lock()
+		uc.poolsClosed = true
+		pools := uc.allPools
+		uc.workersLock.Unlock()
+		for _, pool := range pools {
+			pool.close()
+		}
+		// Wait for all workers to finish before returning (when stopPolling is set)
+		for _, pool := range pools {
+			pool.wait()
 		}
-		close(workQueue)
-	}()
-
-	// Wait for all workers to finish before returning (when stopPolling is set)
-	go func() {
-		workerWg.Wait()
 		log.Println("Worker pool exited gracefully.")
+		close(uc.workerPoolDone)
 	}()
 }
 
+// signalStop stops polling and wakes up the poll loop if it is sleeping.
+func (uc *UnmeshedClient) signalStop() {
+	uc.stopPolling.Store(true)
+	uc.stopSignalOnce.Do(func() {
+		close(uc.stopSignal)
+	})
+}
+
+// sleepUnlessStopped waits for d, returning early when the client is stopped.
+func (uc *UnmeshedClient) sleepUnlessStopped(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-uc.stopSignal:
+	}
+}
+
+// newPollBackoff creates the backoff used between polls. With long polling the
+// server already holds empty polls, so the client keeps the base delay.
+func (uc *UnmeshedClient) newPollBackoff() *pollBackoff {
+	baseMillis := uc.ClientConfig.GetDelayMillis()
+	maxMillis := uc.ClientConfig.GetMaxPollDelayMillis()
+	if uc.ClientConfig.GetLongPollWaitMillis() > 0 {
+		maxMillis = baseMillis
+	}
+	return newPollBackoff(baseMillis, maxMillis, int64(uc.jitterUpperBoundInMillis), int64(uc.backoffMultiplier))
+}
+
+func (uc *UnmeshedClient) runTask(task stepTask) {
+	uc.runStep(task.worker, task.state, &task.workRequest)
+}
+
+// startDedicatedPoolLocked starts the worker's own execution pool if it has one
+// configured. The caller holds workersLock.
+func (uc *UnmeshedClient) startDedicatedPoolLocked(workerId string, worker *workersApi.Worker) {
+	if worker.GetExecutionPoolSize() <= 0 || uc.sharedPool == nil || uc.poolsClosed {
+		return
+	}
+	pool := newExecutionPool(workerId, worker.GetExecutionPoolSize(), worker.GetExecutionPoolQueueDepth(),
+		uc.ClientConfig.GetPriorityAgingMillis(), uc.runTask)
+	uc.dedicatedPools[workerId] = pool
+	uc.allPools = append(uc.allPools, pool)
+}
+
+// dispatch hands a polled work request to the worker's dedicated pool, or to
+// the shared pool when the worker has none. The task keeps references to the
+// worker and its poll state, so it still completes if the worker is removed
+// in the meantime.
+func (uc *UnmeshedClient) dispatch(workRequest common.WorkRequest) {
+	workerId := formattedWorkerID(workRequest.GetStepNamespace(), workRequest.GetStepName())
+
+	uc.workersLock.RLock()
+	foundWorker := uc.workersByID[workerId]
+	state := uc.pollStates[workerId]
+	pool := uc.sharedPool
+	if dedicated := uc.dedicatedPools[workerId]; dedicated != nil {
+		pool = dedicated
+	}
+	uc.workersLock.RUnlock()
+
+	if foundWorker == nil || state == nil {
+		log.Printf("No worker found for step '%s:%s'\n", workRequest.GetStepNamespace(), workRequest.GetStepName())
+		if state != nil {
+			state.Release(1)
+		}
+		uc.executingCount.Add(-1)
+		return
+	}
+
+	if uc.isDuplicate(&workRequest) {
+		// The first delivery is still running or submitting its result and
+		// reports the outcome for both.
+		log.Printf("Skipping duplicate delivery of step execution %d of process %d",
+			workRequest.GetStepExecutionID(), workRequest.GetProcessID())
+		state.Release(1)
+		uc.executingCount.Add(-1)
+		return
+	}
+
+	if !pool.enqueue(stepTask{worker: foundWorker, state: state, workRequest: workRequest}) {
+		// The worker was removed or the client shut down after the pool was
+		// looked up, so the step is left for the server to deliver again.
+		log.Printf("Execution pool %s is closed, dropping step execution %d of process %d",
+			pool.name, workRequest.GetStepExecutionID(), workRequest.GetProcessID())
+		uc.forgetDelivery(workRequest.GetStepExecutionID())
+		state.Release(1)
+		uc.executingCount.Add(-1)
+	}
+}
+
+// isDuplicate reports whether the step execution was already received recently.
+// Work requests without a step execution ID are never considered duplicates.
+func (uc *UnmeshedClient) isDuplicate(workRequest *common.WorkRequest) bool {
+	if uc.dedup == nil || workRequest.GetStepExecutionID() == 0 {
+		return false
+	}
+	if !uc.dedup.markSeen(workRequest.GetStepExecutionID()) {
+		return false
+	}
+	uc.duplicatesSeen.Add(1)
+	return true
+}
+
+// deliveryFinished starts the dedup ttl of a step execution whose worker has
+// returned and whose result is waiting for acknowledgement.
+func (uc *UnmeshedClient) deliveryFinished(stepExecutionID int64) {
+	if uc.dedup != nil && stepExecutionID != 0 {
+		uc.dedup.finished(stepExecutionID)
+	}
+}
+
+// forgetDelivery drops a step execution from the dedup cache, so its next
+// delivery is executed again.
+func (uc *UnmeshedClient) forgetDelivery(stepExecutionID int64) {
+	if uc.dedup != nil && stepExecutionID != 0 {
+		uc.dedup.forget(stepExecutionID)
+	}
+}
+
 func (uc *UnmeshedClient) renewRegistrationWithRetry(renewRegistrationTask interface{}) (string, error) {
 	const delay = 2 * time.Second
 
@@ -420,13 +755,6 @@ func (uc *UnmeshedClient) Start() {
 		return
 	}
 
-	for _, worker := range uc.registrationClient.GetWorkers() {
-		defaultMaxSize := worker.GetMaxInProgress()
-		workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
-
-		uc.pollStates[workerId] = common.NewStepPollState(defaultMaxSize)
-	}
-
 	if !uc.ClientConfig.IsEnableResultsSubmission() {
 		log.Printf("WARN: Batch processing is disabled for results submission")
 		return
@@ -439,6 +767,7 @@ func (uc *UnmeshedClient) Start() {
 	if err != nil {
 		log.Printf("Error renewing registration: %v", err)
 	}
+	go uc.startRegistrationHeartbeat()
 
 	unmeshedHostName := GetHostName()
 	log.Printf("Unmeshed Go SDK started on server : %v", unmeshedHostName)
@@ -471,59 +800,257 @@ func (uc *UnmeshedClient) GetProcessDefinitionVersions(namespace, name string) (
 }
 
 func (uc *UnmeshedClient) registerWorker(worker *workersApi.Worker) error {
-	// Create unique worker ID using namespace and name
-	workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+	return uc.registerAll([]*workersApi.Worker{worker})
+}
 
-	if _, exists := uc.workerByID[workerId]; exists {
-		return fmt.Errorf("worker with namespace '%s' and name '%s' is already registered",
-			worker.GetNamespace(), worker.GetName())
+// registerAll registers either all of the given workers or, when any of them is
+// invalid or already registered, none of them.
+func (uc *UnmeshedClient) registerAll(toRegister []*workersApi.Worker) error {
+	for _, worker := range toRegister {
+		if err := validateWorker(worker); err != nil {
+			return err
+		}
 	}
 
+	uc.workersLock.Lock()
+	defer uc.workersLock.Unlock()
+
+	pending := make(map[string]bool, len(toRegister))
+	for _, worker := range toRegister {
+		workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
+		if _, exists := uc.workerByID[workerId]; exists || pending[workerId] {
+			return fmt.Errorf("worker with namespace '%s' and name '%s' is already registered",
+				worker.GetNamespace(), worker.GetName())
+		}
+		pending[workerId] = true
+	}
+
+	for _, worker := range toRegister {
+		uc.addWorkerLocked(worker)
+	}
+	return nil
+}
+
+// validateWorker checks the execution method of a worker before registration.
+func validateWorker(worker *workersApi.Worker) error {
 	method := worker.GetExecutionMethod()
 	if method == nil {
 		return fmt.Errorf("no execution method found for worker %s:%s",
 			worker.GetNamespace(), worker.GetName())
 	}
 
-	methodType := reflect.TypeOf(method)
-
-	if methodType.NumIn() != 1 {
-		return fmt.Errorf("execution method %s must have exactly one parameter, but found %d",
-			methodType.Name(), methodType.NumIn())
+	signature, err := workersApi.ParseMethodSignature(method)
+	if err != nil {
+		return err
 	}
+	if outputNames := worker.GetOutputNames(); len(outputNames) > 0 && len(outputNames) != signature.OutputCount {
+		return fmt.Errorf("worker %s:%s has %d output names but its execution method returns %d values",
+			worker.GetNamespace(), worker.GetName(), len(outputNames), signature.OutputCount)
+	}
+	return nil
+}
+
+// addWorkerLocked registers a validated worker. The caller holds workersLock.
+func (uc *UnmeshedClient) addWorkerLocked(worker *workersApi.Worker) {
+	// Create unique worker ID using namespace and name
+	workerId := formattedWorkerID(worker.GetNamespace(), worker.GetName())
 
 	uc.workerByID[workerId] = true
 	uc.Workers = append(uc.Workers, *worker)
 	uc.workersByID[workerId] = worker
+	uc.pollStates[workerId] = common.NewStepPollState(worker.GetMaxInProgress())
+	uc.startDedicatedPoolLocked(workerId, worker)
 
 	workers := []workers.Worker{*worker}
 	uc.registrationClient.AddWorkers(workers)
 
 	log.Printf("Registered worker: %s:%s", worker.GetNamespace(), worker.GetName())
+}
 
-	return nil
+// renewRegistrationInBackground re-registers the workers with the server after
+// they changed while the client is running. The renewal runs on the
+// registration heartbeat, which retries it until it succeeds or the client stops.
+func (uc *UnmeshedClient) renewRegistrationInBackground() {
+	if !uc.processingStarted.Load() || uc.stopPolling.Load() {
+		return
+	}
+	uc.renewRegistrationSoon()
 }
 
 func (uc *UnmeshedClient) RegisterWorker(worker *workers.Worker) error {
-	return uc.registerWorker(worker)
+	return uc.AddWorker(worker)
+}
+
+// AddWorker registers a worker. It can be called before or after Start; once
+// the client is running the worker is polled for right away and the
+// registration with the server is renewed.
+func (uc *UnmeshedClient) AddWorker(worker *workers.Worker) error {
+	if err := uc.registerWorker(worker); err != nil {
+		return err
+	}
+	uc.renewRegistrationInBackground()
+	return nil
+}
+
+// RemoveWorker stops polling for a worker and removes it from the registration.
+// Steps of the worker that were already received still run to completion.
+func (uc *UnmeshedClient) RemoveWorker(namespace string, name string) error {
+	workerId := formattedWorkerID(namespace, name)
+
+	uc.workersLock.Lock()
+	if _, exists := uc.workerByID[workerId]; !exists {
+		uc.workersLock.Unlock()
+		return fmt.Errorf("worker with namespace '%s' and name '%s' is not registered", namespace, name)
+	}
+	delete(uc.workerByID, workerId)
+	delete(uc.workersByID, workerId)
+	delete(uc.pollStates, workerId)
+	delete(uc.pausedWorkers, workerId)
+	for i, worker := range uc.Workers {
+		if worker.GetNamespace() == namespace && worker.GetName() == name {
+			uc.Workers = append(uc.Workers[:i:i], uc.Workers[i+1:]...)
+			break
+		}
+	}
+	if pool := uc.dedicatedPools[workerId]; pool != nil {
+		delete(uc.dedicatedPools, workerId)
+		pool.close()
+	}
+	uc.registrationClient.RemoveWorker(namespace, name)
+	uc.workersLock.Unlock()
+
+	log.Printf("Removed worker: %s:%s", namespace, name)
+	uc.renewRegistrationInBackground()
+	return nil
+}
+
+// PauseWorker stops polling for new work for a worker. Steps that are already
+// running finish normally.
+func (uc *UnmeshedClient) PauseWorker(namespace string, name string) error {
+	return uc.setWorkerPaused(namespace, name, true)
+}
+
+// ResumeWorker resumes polling for a worker paused with PauseWorker.
+func (uc *UnmeshedClient) ResumeWorker(namespace string, name string) error {
+	return uc.setWorkerPaused(namespace, name, false)
+}
+
+func (uc *UnmeshedClient) IsWorkerPaused(namespace string, name string) bool {
+	uc.workersLock.RLock()
+	defer uc.workersLock.RUnlock()
+	return uc.pausedWorkers[formattedWorkerID(namespace, name)]
+}
+
+func (uc *UnmeshedClient) setWorkerPaused(namespace string, name string, paused bool) error {
+	workerId := formattedWorkerID(namespace, name)
+
+	uc.workersLock.Lock()
+	defer uc.workersLock.Unlock()
+
+	if _, exists := uc.workerByID[workerId]; !exists {
+		return fmt.Errorf("worker with namespace '%s' and name '%s' is not registered", namespace, name)
+	}
+	if paused {
+		uc.pausedWorkers[workerId] = true
+		log.Printf("Paused worker: %s:%s", namespace, name)
+	} else {
+		delete(uc.pausedWorkers, workerId)
+		log.Printf("Resumed worker: %s:%s", namespace, name)
+	}
+	return nil
 }
 
 func (uc *UnmeshedClient) RegisterWorkers(workers []*workers.Worker) error {
 	for _, worker := range workers {
-		if err := uc.registerWorker(worker); err != nil {
+		if err := uc.AddWorker(worker); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// RegisterWorkerStruct registers the worker methods of obj, those listed in the
+// options or ending in Worker, see workers.NewWorkersFromStruct. Either all of
+// them are registered or, when any fails validation, none are.
+func (uc *UnmeshedClient) RegisterWorkerStruct(obj interface{}, opts *workers.StructOptions) error {
+	structWorkers, err := workersApi.NewWorkersFromStruct(obj, opts)
+	if err != nil {
+		return err
+	}
+	if err := uc.registerAll(structWorkers); err != nil {
+		return err
+	}
+	uc.renewRegistrationInBackground()
+	return nil
+}
+
+// Use registers interceptors that run around every worker execution, outside
+// of the interceptors registered on individual workers. Interceptors have to be
+// registered before Start is called.
+func (uc *UnmeshedClient) Use(interceptors ...workersApi.Interceptor) {
+	uc.workerRunner.Use(interceptors...)
+}
+
+// SetPanicHandler registers a hook that is called whenever a worker panics.
+// The step itself is always reported as FAILED.
+func (uc *UnmeshedClient) SetPanicHandler(handler PanicHandler) {
+	uc.panicHandler = handler
+}
+
 func (uc *UnmeshedClient) Stop() {
-	uc.stopPolling.Store(true)
+	uc.signalStop()
+	uc.cancelRootCtx()
 	uc.stopOnce.Do(func() {
 		close(uc.done)
 	})
 }
 
+// ShutdownSummary describes the work that was still pending when Shutdown returned.
+type ShutdownSummary struct {
+	InFlightSteps int
+	Undelivered   []*common.WorkResponse
+}
+
+// Clean reports whether everything was executed and submitted before shutdown.
+func (s *ShutdownSummary) Clean() bool {
+	return s.InFlightSteps == 0 && len(s.Undelivered) == 0
+}
+
+// Shutdown stops polling, waits for in-flight steps to finish and flushes all
+// pending result submissions. Whatever is still outstanding when ctx is done is
+// reported in the summary together with the context error.
+func (uc *UnmeshedClient) Shutdown(ctx context.Context) (*ShutdownSummary, error) {
+	log.Printf("Shutting down, waiting for in-flight steps to finish")
+	uc.signalStop()
+	defer uc.stopOnce.Do(func() {
+		close(uc.done)
+	})
+	defer uc.cancelRootCtx()
+
+	summary := &ShutdownSummary{}
+	if uc.processingStarted.Load() {
+		select {
+		case <-uc.workerPoolDone:
+		case <-ctx.Done():
+			// Let the remaining workers know that their results will be abandoned.
+			uc.cancelRootCtx()
+			summary.InFlightSteps = int(uc.executingCount.Load())
+		}
+	}
+
+	if uc.submitClient != nil {
+		summary.Undelivered = uc.submitClient.Drain(ctx)
+	}
+
+	if summary.Clean() {
+		log.Printf("Shutdown complete")
+		return summary, nil
+	}
+	log.Printf("Shutdown incomplete: %d steps still running, %d results not delivered",
+		summary.InFlightSteps, len(summary.Undelivered))
+	return summary, ctx.Err()
+}
+
 func (uc *UnmeshedClient) RunProcessSyncWithDefaultTimeout(processRequestData *common.ProcessRequestData) (*common.ProcessData, error) {
 	return uc.processClient.RunProcessSync(processRequestData, 0)
 }
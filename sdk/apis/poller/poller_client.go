@@ -12,6 +12,16 @@ import (
 	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
 )
 
+// PollError is returned when the server rejects a poll.
+type PollError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *PollError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
 type PollerClient struct {
 	clientConfig       *configs.ClientConfig
 	unmeshedHostName   *string
@@ -45,6 +55,9 @@ func (pc *PollerClient) Poll(stepSizes []common.StepSize) ([]common.WorkRequest,
 	params := map[string]interface{}{
 		"size": fmt.Sprintf("%d", pc.clientConfig.GetWorkRequestBatchSize()),
 	}
+	if waitMillis := pc.clientConfig.GetLongPollWaitMillis(); waitMillis > 0 {
+		params["waitMillis"] = fmt.Sprintf("%d", waitMillis)
+	}
 
 	clientPollUrl := pc.CLIENTS_POLL_URL
 
@@ -64,18 +77,19 @@ func (pc *PollerClient) Poll(stepSizes []common.StepSize) ([]common.WorkRequest,
 		errorBody, err := io.ReadAll(response.Body)
 		if err != nil {
 			log.Printf("Did not receive 200! Status: %d, Failed to read error response: %v", response.StatusCode, err)
-			return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+			return nil, &PollError{StatusCode: response.StatusCode}
 		}
 		if len(errorBody) > 0 {
 			log.Printf("Did not receive 200! Status: %d, Error: %s", response.StatusCode, string(errorBody))
 		} else {
 			log.Printf("Did not receive 200! Status: %d", response.StatusCode)
 		}
-		return nil, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		return nil, &PollError{StatusCode: response.StatusCode, Body: string(errorBody)}
 	}
 
 	var workRequests []common.WorkRequest
 	decoder := json.NewDecoder(response.Body)
+	decoder.UseNumber()
 	if err := decoder.Decode(&workRequests); err != nil {
 		log.Printf("Failed to decode JSON response: %v", err)
 		return nil, fmt.Errorf("failed to decode response JSON: %w", err)
@@ -1,12 +1,14 @@
 package apis
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"reflect"
+	"sync"
 	"time"
 
 	apis "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/http"
@@ -20,7 +22,10 @@ type RegistrationClient struct {
 	httpClient         *http.Client
 	requestFactory     *apis.HttpRequestFactory
 	workers            []workers.Worker
+	workersLock        sync.RWMutex
 	clientsRegisterURL string
+	status             RegistrationStatus
+	statusLock         sync.Mutex
 }
 
 const (
@@ -39,89 +44,164 @@ func NewRegistrationClient(clientConfig *configs.ClientConfig, httpClientFactory
 }
 
 func (rc *RegistrationClient) AddWorkers(workers []workers.Worker) {
+	rc.workersLock.Lock()
+	defer rc.workersLock.Unlock()
 	rc.workers = append(rc.workers, workers...)
 }
 
+// RemoveWorker drops a worker from the registration and reports whether it was
+// registered.
+func (rc *RegistrationClient) RemoveWorker(namespace string, name string) bool {
+	rc.workersLock.Lock()
+	defer rc.workersLock.Unlock()
+	for i, worker := range rc.workers {
+		if worker.GetNamespace() == namespace && worker.GetName() == name {
+			rc.workers = append(rc.workers[:i:i], rc.workers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetWorkers returns a snapshot of the registered workers.
 func (rc *RegistrationClient) GetWorkers() []workers.Worker {
-	return rc.workers
+	rc.workersLock.RLock()
+	defer rc.workersLock.RUnlock()
+	snapshot := make([]workers.Worker, len(rc.workers))
+	copy(snapshot, rc.workers)
+	return snapshot
+}
+
+// RegistrationStatus reports the outcome of the most recent registration attempts.
+type RegistrationStatus struct {
+	Registered    bool
+	LastSuccess   time.Time
+	LastAttempt   time.Time
+	LastError     string
+	RenewalsCount int64
+}
+
+// GetRegistrationStatus returns the current registration status.
+func (rc *RegistrationClient) GetRegistrationStatus() RegistrationStatus {
+	rc.statusLock.Lock()
+	defer rc.statusLock.Unlock()
+	return rc.status
+}
+
+func (rc *RegistrationClient) recordAttempt(err error) {
+	rc.statusLock.Lock()
+	defer rc.statusLock.Unlock()
+	now := time.Now()
+	rc.status.LastAttempt = now
+	if err != nil {
+		rc.status.LastError = err.Error()
+		return
+	}
+	rc.status.Registered = true
+	rc.status.LastSuccess = now
+	rc.status.LastError = ""
+	rc.status.RenewalsCount++
 }
 
+// RenewRegistration registers the workers with the server, retrying until it
+// succeeds.
 func (rc *RegistrationClient) RenewRegistration() (string, error) {
-    supportedSteps := make([]map[string]interface{}, 0)
-
-    for _, worker := range rc.workers {
-        step := map[string]interface{}{
-            "orgId":     0,
-            "namespace": worker.GetNamespace(),
-            "stepType":  "WORKER",
-            "name":      worker.GetName(),
-        }
-        supportedSteps = append(supportedSteps, step)
-    }
-
-    log.Printf("Renewing registration for the following workers: %v", supportedSteps)
-
-    data, err := json.Marshal(supportedSteps)
-    if err != nil {
-        return "", fmt.Errorf("failed to marshal supported steps: %w", err)
-    }
-
-    params := map[string]interface{}{}
-    delay := 1 * time.Second
-    maxDelay := 10 * time.Second
-    retryCount := 0
-
-    for {
-        log.Printf("Attempting to renew registration. Retry count: %d", retryCount)
-        response, err := rc.requestFactory.CreatePutRequest(rc.clientsRegisterURL, params, data)
-        if err == nil {
-            defer response.Body.Close()
-
-            if response.StatusCode == http.StatusOK {
-                body, err := io.ReadAll(response.Body)
-                if err != nil {
-                    return "", fmt.Errorf("failed to read response body: %w", err)
-                }
-                retryCount = 0
-                log.Printf("Successfully renewed registration for workers.")
-                return string(body), nil
-            }
-
-            errorBody, err := io.ReadAll(response.Body)
-            if err != nil {
-                log.Printf("Did not receive 200! Status: %d, Failed to read error response: %v", response.StatusCode, err)
-                retryCount++
-                log.Printf("Retry %d failed: %s:%s", retryCount, "HTTPError", fmt.Sprintf("status code %d", response.StatusCode))
-            } else {
-                if len(errorBody) > 0 {
-                    log.Printf("Did not receive 200! Status: %d, Error: %s", response.StatusCode, string(errorBody))
-                } else {
-                    log.Printf("Did not receive 200! Status: %d", response.StatusCode)
-                }
-                retryCount++
-                log.Printf("Retry %d failed: %s:%s", retryCount, "HTTPError", fmt.Sprintf("status code %d", response.StatusCode))
-            }
-        } else {
-            retryCount++
-            log.Printf("Retry %d failed: %s:%v", retryCount, reflect.TypeOf(err).Name(), err)
-        }
-
-        log.Printf("Waiting for %d seconds before retrying...", int(delay.Seconds()))
-        time.Sleep(delay)
-
-        // Increment delay, capping at maxDelay
-        if delay < maxDelay {
-            delay += 2 * time.Second
-            if delay > maxDelay {
-                delay = maxDelay
-            }
-        }
-    }
+	return rc.RenewRegistrationContext(context.Background())
+}
+
+// RenewRegistrationContext retries the registration like RenewRegistration
+// until it succeeds or ctx is done.
+func (rc *RegistrationClient) RenewRegistrationContext(ctx context.Context) (string, error) {
+	delay := 1 * time.Second
+	maxDelay := 10 * time.Second
+	retryCount := 0
+
+	for {
+		log.Printf("Attempting to renew registration. Retry count: %d", retryCount)
+		body, err := rc.RenewRegistrationOnce()
+		if err == nil {
+			return body, nil
+		}
+		retryCount++
+		log.Printf("Retry %d failed: %v", retryCount, err)
+
+		log.Printf("Waiting for %d seconds before retrying...", int(delay.Seconds()))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+
+		// Increment delay, capping at maxDelay
+		if delay < maxDelay {
+			delay += 2 * time.Second
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+		}
+	}
+}
+
+// RenewRegistrationOnce makes a single registration attempt and records its
+// outcome in the registration status.
+func (rc *RegistrationClient) RenewRegistrationOnce() (string, error) {
+	body, err := rc.renewRegistration()
+	rc.recordAttempt(err)
+	return body, err
+}
+
+func (rc *RegistrationClient) renewRegistration() (string, error) {
+	supportedSteps := make([]map[string]interface{}, 0)
+
+	for _, worker := range rc.GetWorkers() {
+		step := map[string]interface{}{
+			"orgId":     0,
+			"namespace": worker.GetNamespace(),
+			"stepType":  "WORKER",
+			"name":      worker.GetName(),
+		}
+		supportedSteps = append(supportedSteps, step)
+	}
+
+	log.Printf("Renewing registration for the following workers: %v", supportedSteps)
+
+	data, err := json.Marshal(supportedSteps)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal supported steps: %w", err)
+	}
+
+	params := map[string]interface{}{}
+	response, err := rc.requestFactory.CreatePutRequest(rc.clientsRegisterURL, params, data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", reflect.TypeOf(err).String(), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read response body: %w", err)
+		}
+		log.Printf("Successfully renewed registration for workers.")
+		return string(body), nil
+	}
+
+	errorBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Printf("Did not receive 200! Status: %d, Failed to read error response: %v", response.StatusCode, err)
+	} else if len(errorBody) > 0 {
+		log.Printf("Did not receive 200! Status: %d, Error: %s", response.StatusCode, string(errorBody))
+	} else {
+		log.Printf("Did not receive 200! Status: %d", response.StatusCode)
+	}
+	return "", fmt.Errorf("HTTPError: status code %d", response.StatusCode)
 }
 
 func (rc *RegistrationClient) GetWorkerStepNames() []common.WorkerStepName {
 	stepNames := make([]common.WorkerStepName, 0)
-	for _, worker := range rc.workers {
+	for _, worker := range rc.GetWorkers() {
 		stepName := common.WorkerStepName{
 			StepQueueNameData: common.StepQueueNameData{
 				OrgId:     0,
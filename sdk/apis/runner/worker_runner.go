@@ -1,80 +1,203 @@
 package apis
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"runtime/debug"
+	"time"
 
 	"github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
 	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
 )
 
+// rateLimitRetryInterval is how often a retry waiting on the worker's rate
+// limit checks for a new token.
+const rateLimitRetryInterval = 50 * time.Millisecond
+
 type FunctionWrapper struct {
-	Fn  interface{}
-	Arg interface{}
+	Fn          interface{}
+	Arg         interface{}
+	WorkRequest *common.WorkRequest
+	OutputNames []string
 }
 
-type WorkerRunner struct{}
+type WorkerRunner struct {
+	interceptors []workers.Interceptor
+}
 
 func NewWorkerRunner() *WorkerRunner {
 	return &WorkerRunner{}
 }
 
-func (wr *WorkerRunner) RunWorker(worker *workers.Worker, workRequest *common.WorkRequest) (interface{}, error) {
+// Use adds interceptors that run around every worker execution. They wrap the
+// interceptors registered on the individual workers.
+func (wr *WorkerRunner) Use(interceptors ...workers.Interceptor) {
+	wr.interceptors = append(wr.interceptors, interceptors...)
+}
+
+// RunWorker executes the worker for a work request through the interceptor chain.
+// The context is passed on to execution methods that accept a context.Context as
+// their first parameter and is cancelled when the step times out or the client
+// stops.
+//
+// The context carries a common.StepContext for the work request, created here
+// unless the caller already attached one.
+//
+// A panic raised by the execution method or an interceptor is recovered and
+// returned as a *common.PanicError so the calling goroutine keeps running.
+//
+// When the worker has a retry policy, failed attempts are retried through the
+// whole chain until the policy gives up. The step context's Attempt is updated
+// for every attempt, and a step that failed more than once returns a
+// *common.RetryError holding the attempt history.
+func (wr *WorkerRunner) RunWorker(ctx context.Context, worker *workers.Worker, workRequest *common.WorkRequest) (*common.StepResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stepContext := common.StepContextFrom(ctx)
+	if stepContext == nil {
+		stepContext = common.NewStepContext(workRequest)
+	}
+
+	handler := workers.Chain(func(ctx context.Context, workRequest *common.WorkRequest) (*common.StepResult, error) {
+		result, err := wr.invoke(ctx, worker, workRequest)
+		if err != nil {
+			return nil, err
+		}
+		return toStepResult(result), nil
+	}, worker.GetInterceptors()...)
+	handler = workers.Chain(handler, wr.interceptors...)
+
+	retryPolicy := worker.GetRetryPolicy()
+	var attempts []common.AttemptRecord
+	for attempt := 1; ; attempt++ {
+		attemptContext := *stepContext
+		attemptContext.Attempt = attempt
+		startedAt := time.Now()
+		stepResult, err := runHandler(common.WithStepContext(ctx, &attemptContext), handler, workRequest)
+		if err == nil {
+			return stepResult, nil
+		}
+
+		attempts = append(attempts, common.AttemptRecord{
+			Attempt:        attempt,
+			Error:          err.Error(),
+			StartedAt:      startedAt.UnixMilli(),
+			DurationMillis: time.Since(startedAt).Milliseconds(),
+		})
+		if retryPolicy == nil || attempt >= retryPolicy.MaxAttempts || !retryPolicy.IsRetryable(err) ||
+			!sleepContext(ctx, retryPolicy.Backoff(attempt)) || !acquireToken(ctx, worker.GetRateLimiter()) {
+			if len(attempts) == 1 {
+				return nil, err
+			}
+			return nil, &common.RetryError{Attempts: attempts, Last: err}
+		}
+		stepContext.Logger.Printf("Attempt %d failed, retrying: %v", attempt, err)
+	}
+}
+
+func runHandler(ctx context.Context, handler workers.Handler, workRequest *common.WorkRequest) (stepResult *common.StepResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stepResult = nil
+			err = common.NewPanicError(r, debug.Stack())
+		}
+	}()
+	return handler(ctx, workRequest)
+}
+
+// acquireToken takes a token from the worker's rate limiter before a retry, so
+// retries count against the same limit as polled executions. It reports false
+// when ctx is done before a token becomes available.
+func acquireToken(ctx context.Context, rateLimiter *common.TokenBucket) bool {
+	if rateLimiter == nil {
+		return true
+	}
+	for rateLimiter.TryAcquire(1) == 0 {
+		if !sleepContext(ctx, rateLimitRetryInterval) {
+			return false
+		}
+	}
+	return true
+}
+
+// sleepContext waits for the given duration and reports false when ctx is done
+// first.
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (wr *WorkerRunner) invoke(ctx context.Context, worker *workers.Worker, workRequest *common.WorkRequest) (interface{}, error) {
+	if invoke := worker.GetInvokeFunc(); invoke != nil {
+		result, err := invoke(ctx, workRequest)
+		if err != nil || len(worker.GetOutputNames()) == 0 {
+			return result, err
+		}
+		return namedOutputs(worker.GetOutputNames(), []interface{}{result})
+	}
+
 	wrapper := FunctionWrapper{
-		Fn:  worker.ExecutionMethod,
-		Arg: workRequest.InputParam,
+		Fn:          worker.ExecutionMethod,
+		Arg:         workRequest.InputParam,
+		WorkRequest: workRequest,
+		OutputNames: worker.GetOutputNames(),
 	}
-	result, err := wr.invokeFunction(wrapper)
+	result, err := wr.invokeFunction(ctx, wrapper)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (wr *WorkerRunner) invokeFunction(f FunctionWrapper) (interface{}, error) {
+func toStepResult(result interface{}) *common.StepResult {
+	if sr, ok := result.(*common.StepResult); ok && sr != nil {
+		return sr
+	}
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	return common.NewStepResult(result)
+}
+
+func (wr *WorkerRunner) invokeFunction(ctx context.Context, f FunctionWrapper) (interface{}, error) {
 	fnType := reflect.TypeOf(f.Fn)
-	if fnType.Kind() != reflect.Func {
+	if fnType == nil || fnType.Kind() != reflect.Func {
 		log.Printf("Skipping invalid function: %+v (Incorrect signature)\n", f.Fn)
 		return nil, errors.New("Skipping invalid function")
 	}
 
-	if fnType.NumIn() != 1 {
-		log.Printf("Function must accept exactly one argument: %+v\n", f.Fn)
-		return nil, errors.New("Function must accept exactly one argument")
+	signature, err := workers.ParseMethodSignature(f.Fn)
+	if err != nil {
+		log.Printf("Function has an unsupported signature: %+v\n", f.Fn)
+		return nil, err
 	}
 
-	argType := fnType.In(0)
-	argValue := reflect.ValueOf(f.Arg)
-
-	if argValue.Type().Kind() == reflect.Map && argValue.Type().Key().Kind() == reflect.String ||
-		argValue.Type().Kind() == reflect.Slice {
-		targetValue := reflect.New(argType).Elem()
-		jsonData, err := json.Marshal(f.Arg)
-		if err != nil {
-			log.Printf("JSON marshal error: %v\n", err)
-			return nil, err
-		}
-		err = json.Unmarshal(jsonData, targetValue.Addr().Interface())
+	var args []reflect.Value
+	if signature.HasContext {
+		args = append(args, reflect.ValueOf(ctx))
+	}
+	if signature.HasWorkRequest {
+		args = append(args, reflect.ValueOf(f.WorkRequest))
+	}
+	if signature.InputType != nil {
+		argValue, err := decodeArgument(signature.InputType, f.Arg)
 		if err != nil {
-			log.Printf("JSON unmarshal error: %v\n", err)
 			return nil, err
 		}
-		argValue = targetValue
-	} else {
-		log.Printf("Invalid input type for function: %+v\n", f.Fn)
-		return nil, fmt.Errorf("Argument must be map[string]interface{} or []interface{}")
-	}
-
-	if argType.Kind() == reflect.Ptr && argValue.Kind() != reflect.Ptr {
-		argValue = argValue.Addr()
-	} else if argType.Kind() != reflect.Ptr && argValue.Kind() == reflect.Ptr {
-		argValue = argValue.Elem()
+		args = append(args, argValue)
 	}
 
-	rawResults := reflect.ValueOf(f.Fn).Call([]reflect.Value{argValue})
+	rawResults := reflect.ValueOf(f.Fn).Call(args)
 	numResults := len(rawResults)
 
 	if numResults == 0 {
@@ -103,7 +226,14 @@ func (wr *WorkerRunner) invokeFunction(f FunctionWrapper) (interface{}, error) {
 		finalResults = append(finalResults, rawResults[i].Interface())
 	}
 
+	if len(f.OutputNames) > 0 {
+		return namedOutputs(f.OutputNames, finalResults)
+	}
+
 	if len(finalResults) == 1 {
+		if finalResults[0] == nil {
+			return nil, nil
+		}
 		// Unwrap single result (not slice)
 		kind := reflect.TypeOf(finalResults[0]).Kind()
 		if kind != reflect.Slice && kind != reflect.Array {
@@ -114,8 +244,43 @@ func (wr *WorkerRunner) invokeFunction(f FunctionWrapper) (interface{}, error) {
 	return finalResults, nil
 }
 
+// namedOutputs keys the return values of a worker by its output names. A
+// *common.StepResult is passed through unchanged so that its reschedule,
+// checkpoint and deferral settings are kept.
+func namedOutputs(names []string, values []interface{}) (interface{}, error) {
+	if len(values) == 1 {
+		if stepResult, ok := values[0].(*common.StepResult); ok && stepResult != nil {
+			return stepResult, nil
+		}
+	}
+	if len(names) != len(values) {
+		return nil, fmt.Errorf("expected %d return values for output names %v, but found %d", len(names), names, len(values))
+	}
+	output := make(map[string]interface{}, len(names))
+	for i, name := range names {
+		output[name] = values[i]
+	}
+	return output, nil
+}
+
+func decodeArgument(argType reflect.Type, arg interface{}) (reflect.Value, error) {
+	argValue := reflect.ValueOf(arg)
+
+	if argValue.Type().Kind() == reflect.Map && argValue.Type().Key().Kind() == reflect.String ||
+		argValue.Type().Kind() == reflect.Slice {
+		decoded, err := workers.DecodeInput(arg, argType)
+		if err != nil {
+			log.Printf("Input decode error: %v\n", err)
+			return reflect.Value{}, err
+		}
+		return decoded, nil
+	}
+	log.Printf("Invalid input type for argument: %v\n", argType)
+	return reflect.Value{}, fmt.Errorf("Argument must be map[string]interface{} or []interface{}")
+}
+
 func (wr *WorkerRunner) invokeFunctions(functions []FunctionWrapper) {
 	for _, f := range functions {
-		_, _ = wr.invokeFunction(f)
+		_, _ = wr.invokeFunction(context.Background(), f)
 	}
 }
@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,9 +34,10 @@ type SubmitClient struct {
 	submitTracker      map[int64]*common.WorkResponseTracker
 	submitTrackerLock  sync.Mutex
 	stopPolling        atomic.Bool
+	stopSignal         chan struct{}
+	stopOnce           sync.Once
 	workerWg           sync.WaitGroup
 	cleanupWg          sync.WaitGroup
-	lastLogTime        time.Time
 }
 
 func NewSubmitClient(httpRequestFactory *apis.HttpRequestFactory, clientConfig *configs.ClientConfig) *SubmitClient {
@@ -54,6 +56,7 @@ func NewSubmitClient(httpRequestFactory *apis.HttpRequestFactory, clientConfig *
 		mainQueue:          common.NewQueue(100000),
 		retryQueue:         common.NewQueue(100000),
 		submitTracker:      make(map[int64]*common.WorkResponseTracker),
+		stopSignal:         make(chan struct{}),
 	}
 
 	disabled := strings.ToLower(os.Getenv("DISABLE_SUBMIT_CLIENT")) == "true"
@@ -70,10 +73,46 @@ func NewSubmitClient(httpRequestFactory *apis.HttpRequestFactory, clientConfig *
 
 func (c *SubmitClient) Stop() {
 	c.stopPolling.Store(true)
+	c.stopOnce.Do(func() {
+		close(c.stopSignal)
+	})
 	c.workerWg.Wait()
 	c.cleanupWg.Wait()
 }
 
+// Drain keeps submitting until every tracked result has been acknowledged or
+// ctx is done, then stops the client. Results that could not be delivered are
+// returned to the caller. The submitters are always stopped before the
+// undelivered results are collected, so Drain can outlast ctx by one in-flight
+// batch submission.
+func (c *SubmitClient) Drain(ctx context.Context) []*common.WorkResponse {
+	interval := time.Duration(c.clientConfig.GetSubmitClientSleepIntervalMillis()) * time.Millisecond
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+waitLoop:
+	for c.GetSubmitTrackerSize() > 0 {
+		select {
+		case <-ctx.Done():
+			break waitLoop
+		case <-ticker.C:
+		}
+	}
+
+	c.Stop()
+
+	c.submitTrackerLock.Lock()
+	defer c.submitTrackerLock.Unlock()
+	undelivered := make([]*common.WorkResponse, 0, len(c.submitTracker))
+	for _, tracker := range c.submitTracker {
+		undelivered = append(undelivered, tracker.WorkResponse)
+	}
+	return undelivered
+}
+
 func (c *SubmitClient) cleanupLingeringSubmitTrackers() {
 	ticker := time.NewTicker(5 * time.Second)
 	defer func() {
@@ -85,12 +124,12 @@ func (c *SubmitClient) cleanupLingeringSubmitTrackers() {
 		c.submitTrackerLock.Lock()
 		for stepID, tracker := range c.submitTracker {
 			if currentMillis-tracker.QueuedTime > 10*60*1000 {
-				tracker.StepPollState.Release(1)
 				delete(c.submitTracker, stepID)
+				resolve(tracker)
 			}
 		}
 		c.submitTrackerLock.Unlock()
-		time.Sleep(3 * time.Second)
+		c.sleepUnlessStopped(3 * time.Second)
 	}
 }
 
@@ -100,6 +139,8 @@ func (c *SubmitClient) processQueue(queue *common.Queue, queueType string) {
 	if timeout <= 0 {
 		timeout = 30
 	}
+	// Each queue logs on its own, so the time of the last log is per goroutine.
+	var lastLogTime time.Time
 	for !c.stopPolling.Load() {
 		var batch []*common.WorkResponse
 
@@ -108,12 +149,12 @@ func (c *SubmitClient) processQueue(queue *common.Queue, queueType string) {
 
 		// If no items collected, wait a bit and continue
 		if len(batch) == 0 {
-			time.Sleep(time.Duration(c.clientConfig.GetSubmitClientSleepIntervalMillis()) * time.Millisecond)
+			c.sleepUnlessStopped(time.Duration(c.clientConfig.GetSubmitClientSleepIntervalMillis()) * time.Millisecond)
 
 			// Log only once every 30 seconds
-			if time.Since(c.lastLogTime) >= 30*time.Second {
+			if time.Since(lastLogTime) >= 30*time.Second {
 				log.Printf("No item received from queue %s in %d seconds, retrying...", queueType, timeout)
-				c.lastLogTime = time.Now()
+				lastLogTime = time.Now()
 			}
 
 			continue
@@ -121,7 +162,7 @@ func (c *SubmitClient) processQueue(queue *common.Queue, queueType string) {
 
 		if err := c.processBatch(batch); err != nil {
 			log.Printf("Bulk request failed for batch. Re-queuing all items. Error: %v", err)
-			time.Sleep(3 * time.Second)
+			c.sleepUnlessStopped(3 * time.Second)
 			for _, workResponse := range batch {
 				c.handleAllRequestFailure(workResponse, err.Error())
 			}
@@ -129,35 +170,104 @@ func (c *SubmitClient) processQueue(queue *common.Queue, queueType string) {
 	}
 }
 
+// sleepUnlessStopped waits for d, returning early when the client is stopped.
+func (c *SubmitClient) sleepUnlessStopped(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-c.stopSignal:
+	}
+}
+
 func (c *SubmitClient) processBatch(batch []*common.WorkResponse) error {
-	bodyBytes, err := json.Marshal(batch)
+	batch = c.withoutSuperseded(batch)
+	if len(batch) == 0 {
+		return nil
+	}
+	responseMap, err := c.postBatch(batch)
 	if err != nil {
 		return err
 	}
+	c.processBatchResults(batch, responseMap)
+	return nil
+}
+
+func (c *SubmitClient) postBatch(batch []*common.WorkResponse) (map[string]*common.ClientSubmitResult, error) {
+	bodyBytes, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
 	params := map[string]interface{}{}
 	resp, err := c.httpRequestFactory.CreatePostRequest(CLIENTS_RESULTS_URL, params, bodyBytes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		errorBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("response status %d: %s", resp.StatusCode, string(errorBody))
+		return nil, fmt.Errorf("response status %d: %s", resp.StatusCode, string(errorBody))
 	}
 	var responseMap map[string]*common.ClientSubmitResult
 	if err := json.NewDecoder(resp.Body).Decode(&responseMap); err != nil {
+		return nil, err
+	}
+	return responseMap, nil
+}
+
+// SubmitSync submits a single work response right away, bypassing the queues,
+// and returns the error reported by the server for it.
+func (c *SubmitClient) SubmitSync(workResponse *common.WorkResponse) error {
+	responseMap, err := c.postBatch([]*common.WorkResponse{workResponse})
+	if err != nil {
 		return err
 	}
-	c.processBatchResults(batch, responseMap)
+	result, exists := responseMap[fmt.Sprintf("%d", workResponse.GetStepID())]
+	if !exists {
+		return fmt.Errorf("no result returned for step %d", workResponse.GetStepID())
+	}
+	if result != nil && result.GetErrorMessage() != "" {
+		return fmt.Errorf("failed to submit result for step %d: %s", workResponse.GetStepID(), result.GetErrorMessage())
+	}
 	return nil
 }
 
+// trackerFor returns the tracker of a work response, or nil when the response
+// is no longer tracked, for example after a newer result of the same step
+// replaced it.
+func (c *SubmitClient) trackerFor(workResponse *common.WorkResponse) *common.WorkResponseTracker {
+	c.submitTrackerLock.Lock()
+	defer c.submitTrackerLock.Unlock()
+	tracker := c.submitTracker[workResponse.GetStepID()]
+	if tracker == nil || tracker.WorkResponse != workResponse {
+		return nil
+	}
+	return tracker
+}
+
+// withoutSuperseded drops the responses of steps for which a different response
+// is tracked, such as a progress update queued before the step's result. The
+// server acknowledges results per step, so such a response must not share a
+// batch with the result or reach the server after it.
+func (c *SubmitClient) withoutSuperseded(batch []*common.WorkResponse) []*common.WorkResponse {
+	c.submitTrackerLock.Lock()
+	defer c.submitTrackerLock.Unlock()
+	current := batch[:0:0]
+	for _, workResponse := range batch {
+		tracker := c.submitTracker[workResponse.GetStepID()]
+		if tracker != nil && tracker.WorkResponse != workResponse {
+			log.Printf("Dropping superseded %v response of stepId %d", workResponse.GetStatus(), workResponse.GetStepID())
+			continue
+		}
+		current = append(current, workResponse)
+	}
+	return current
+}
+
 func (c *SubmitClient) processBatchResults(batch []*common.WorkResponse, responseMap map[string]*common.ClientSubmitResult) {
 	for _, workResponse := range batch {
 		stepId := fmt.Sprintf("%d", workResponse.GetStepID())
-		c.submitTrackerLock.Lock()
-		workResponseTracker := c.submitTracker[workResponse.GetStepID()]
-		c.submitTrackerLock.Unlock()
+		workResponseTracker := c.trackerFor(workResponse)
 		result, exists := responseMap[stepId]
 		if !exists || (result != nil && len(result.GetErrorMessage()) != 0) {
 			errorMessage := "No result"
@@ -168,20 +278,18 @@ func (c *SubmitClient) processBatchResults(batch []*common.WorkResponse, respons
 			c.enqueueForRetry(workResponse, result, workResponseTracker)
 		} else {
 			log.Printf("Result from stepId %d %d submitted!", workResponse.GetProcessID(), workResponse.GetStepID())
-			c.submitTrackerLock.Lock()
-			delete(c.submitTracker, workResponse.GetStepID())
-			c.submitTrackerLock.Unlock()
 			if workResponseTracker != nil {
-				workResponseTracker.StepPollState.Release(1)
+				c.submitTrackerLock.Lock()
+				delete(c.submitTracker, workResponse.GetStepID())
+				c.submitTrackerLock.Unlock()
+				resolve(workResponseTracker)
 			}
 		}
 	}
 }
 
 func (c *SubmitClient) handleAllRequestFailure(workResponse *common.WorkResponse, message string) {
-	c.submitTrackerLock.Lock()
-	workResponseTracker := c.submitTracker[workResponse.GetStepID()]
-	c.submitTrackerLock.Unlock()
+	workResponseTracker := c.trackerFor(workResponse)
 	if workResponseTracker != nil {
 		c.enqueueForRetry(workResponse, common.NewClientSubmitResult(workResponse.ProcessID, workResponse.StepID, 400, message), workResponseTracker)
 	}
@@ -196,7 +304,7 @@ func (c *SubmitClient) enqueueForRetry(workResponse *common.WorkResponse, result
 		c.submitTrackerLock.Lock()
 		delete(c.submitTracker, workResponse.GetStepID())
 		c.submitTrackerLock.Unlock()
-		workResponseTracker.StepPollState.Release(1)
+		resolve(workResponseTracker)
 		return
 	}
 	count := workResponseTracker.RetryCount + 1
@@ -205,7 +313,7 @@ func (c *SubmitClient) enqueueForRetry(workResponse *common.WorkResponse, result
 		c.submitTrackerLock.Lock()
 		delete(c.submitTracker, workResponse.GetStepID())
 		c.submitTrackerLock.Unlock()
-		workResponseTracker.StepPollState.Release(1)
+		resolve(workResponseTracker)
 		return
 	}
 	workResponseTracker.RetryCount = count
@@ -213,6 +321,15 @@ func (c *SubmitClient) enqueueForRetry(workResponse *common.WorkResponse, result
 	log.Printf("Re-queued WorkResponse %d for retry attempt %d", workResponse.GetProcessID(), count)
 }
 
+// resolve releases the permit held by a tracked response once it no longer
+// needs to be submitted.
+func resolve(tracker *common.WorkResponseTracker) {
+	tracker.StepPollState.Release(1)
+	if tracker.OnResolved != nil {
+		tracker.OnResolved()
+	}
+}
+
 func (c *SubmitClient) isPermanentError(result *common.ClientSubmitResult) bool {
 	if result == nil || result.GetErrorMessage() == "" {
 		return false
@@ -226,11 +343,18 @@ func (c *SubmitClient) isPermanentError(result *common.ClientSubmitResult) bool
 }
 
 func (c *SubmitClient) Submit(workResponse *common.WorkResponse, stepPollState *common.StepPollState) {
+	c.SubmitWithCallback(workResponse, stepPollState, nil)
+}
+
+// SubmitWithCallback queues a work response like Submit and calls onResolved
+// once the server acknowledged it or the client gave up submitting it.
+func (c *SubmitClient) SubmitWithCallback(workResponse *common.WorkResponse, stepPollState *common.StepPollState, onResolved func()) {
 	log.Printf("Submitting results to queue: %+v", workResponse)
 	epochMillis := time.Now().UnixMilli()
 	tracker := common.NewWorkResponseTracker(workResponse)
 	tracker.QueuedTime = epochMillis
 	tracker.StepPollState = stepPollState
+	tracker.OnResolved = onResolved
 	tracker.RetryCount = 0
 	c.submitTrackerLock.Lock()
 	c.submitTracker[workResponse.GetStepID()] = tracker
@@ -239,6 +363,17 @@ func (c *SubmitClient) Submit(workResponse *common.WorkResponse, stepPollState *
 	log.Printf("Result[%v] from stepId %d queued!", workResponse.GetStatus(), workResponse.GetStepID())
 }
 
+// SubmitProgress queues an intermediate RUNNING response of a step. Progress
+// updates are not tracked: they are submitted once, in order with the other
+// results of the step, and dropped when the submission fails or a result of
+// the same step has been queued since.
+func (c *SubmitClient) SubmitProgress(workResponse *common.WorkResponse) error {
+	if !c.mainQueue.Put(workResponse) {
+		return fmt.Errorf("submit queue is full, dropping progress of step %d", workResponse.GetStepID())
+	}
+	return nil
+}
+
 func (c *SubmitClient) GetSubmitTrackerSize() int {
 	c.submitTrackerLock.Lock()
 	defer c.submitTrackerLock.Unlock()
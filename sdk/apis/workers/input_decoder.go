@@ -0,0 +1,87 @@
+package workers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	bytesType      = reflect.TypeOf([]byte(nil))
+)
+
+// DecodeInput converts a step input into a value of targetType.
+//
+// json.RawMessage and []byte receive the input as raw JSON. Scalar types such
+// as string, bool and the numeric types receive the single value of an input
+// with exactly one entry. Any other type is decoded from the JSON form of the
+// input.
+func DecodeInput(input interface{}, targetType reflect.Type) (reflect.Value, error) {
+	baseType := targetType
+	if targetType.Kind() == reflect.Ptr {
+		baseType = targetType.Elem()
+	}
+	target := reflect.New(baseType)
+
+	switch {
+	case baseType == rawMessageType || baseType == bytesType:
+		jsonData, err := json.Marshal(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to marshal input: %w", err)
+		}
+		target.Elem().SetBytes(jsonData)
+	case isScalar(baseType):
+		value, err := singleInputValue(input, baseType)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if err := decodeJSON(value, target.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	default:
+		if err := decodeJSON(input, target.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		return target, nil
+	}
+	return target.Elem(), nil
+}
+
+func decodeJSON(value interface{}, target interface{}) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal input: %w", err)
+	}
+	if err := types.UnmarshalPreservingNumbers(jsonData, target); err != nil {
+		return fmt.Errorf("failed to decode input into %s: %w", reflect.TypeOf(target).Elem(), err)
+	}
+	return nil
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func singleInputValue(input interface{}, targetType reflect.Type) (interface{}, error) {
+	inputMap, ok := input.(map[string]interface{})
+	if !ok || len(inputMap) != 1 {
+		return nil, fmt.Errorf("a %s parameter needs an input with exactly one value", targetType)
+	}
+	for _, value := range inputMap {
+		return value, nil
+	}
+	return nil, nil
+}
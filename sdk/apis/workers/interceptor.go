@@ -0,0 +1,23 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+// Handler executes a step and produces its result.
+type Handler func(ctx context.Context, workRequest *types.WorkRequest) (*types.StepResult, error)
+
+// Interceptor wraps a Handler to run cross-cutting logic such as logging,
+// metrics or input validation around every step execution.
+type Interceptor func(next Handler) Handler
+
+// Chain wraps handler with the given interceptors. The first interceptor is the
+// outermost one and therefore runs first.
+func Chain(handler Handler, interceptors ...Interceptor) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		handler = interceptors[i](handler)
+	}
+	return handler
+}
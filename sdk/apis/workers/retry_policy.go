@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+// RetryPolicy retries a failed execution locally before the step is reported
+// as failed. Attempts are spaced by an exponential backoff starting at
+// InitialBackoff, growing by Multiplier and capped at MaxBackoff.
+//
+// Retryable decides which errors are retried. When nil, a *common.StepError is
+// retried according to its Retryable flag and every other error is retried.
+// Panics and errors caused by the step context being cancelled are never
+// retried.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Retryable      func(err error) bool
+}
+
+// Backoff returns the delay before the attempt following the given one.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(delay)
+}
+
+// IsRetryable reports whether an execution that failed with err may be retried.
+func (p *RetryPolicy) IsRetryable(err error) bool {
+	var panicErr *types.PanicError
+	if errors.As(err, &panicErr) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var stepErr *types.StepError
+	if errors.As(err, &stepErr) {
+		return stepErr.Retryable
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+var (
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	workRequestType = reflect.TypeOf((*types.WorkRequest)(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// MethodSignature describes how an execution method has to be invoked.
+// InputType is nil when the method takes no input.
+// OutputCount is the number of return values besides a trailing error.
+type MethodSignature struct {
+	HasContext     bool
+	HasWorkRequest bool
+	InputType      reflect.Type
+	OutputCount    int
+}
+
+// ParseMethodSignature validates an execution method and returns its signature.
+// The method takes an optional context.Context, then an optional
+// *common.WorkRequest and then an optional input, e.g. func(in T),
+// func(ctx context.Context, in T), func(workRequest *common.WorkRequest),
+// func(ctx context.Context, workRequest *common.WorkRequest, in T) or func().
+func ParseMethodSignature(method interface{}) (*MethodSignature, error) {
+	if method == nil {
+		return nil, fmt.Errorf("execution method cannot be nil")
+	}
+	methodType := reflect.TypeOf(method)
+	if methodType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("execution method must be a function, but found %s", methodType.Kind())
+	}
+
+	signature := &MethodSignature{}
+	params := make([]reflect.Type, 0, methodType.NumIn())
+	for i := 0; i < methodType.NumIn(); i++ {
+		params = append(params, methodType.In(i))
+	}
+
+	if len(params) > 0 && params[0] == contextType {
+		signature.HasContext = true
+		params = params[1:]
+	}
+
+	if len(params) > 0 && params[0] == workRequestType {
+		signature.HasWorkRequest = true
+		params = params[1:]
+	}
+
+	if len(params) > 1 {
+		return nil, fmt.Errorf("execution method %s must have at most one input parameter besides an optional context.Context and *common.WorkRequest, but found %d",
+			methodType.String(), methodType.NumIn())
+	}
+	if len(params) == 1 {
+		signature.InputType = params[0]
+	}
+
+	signature.OutputCount = methodType.NumOut()
+	if signature.OutputCount > 0 && methodType.Out(signature.OutputCount-1).Implements(errorType) {
+		signature.OutputCount--
+	}
+
+	return signature, nil
+}
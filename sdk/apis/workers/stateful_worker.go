@@ -0,0 +1,43 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+// NewStatefulWorker creates a worker for steps that reschedule themselves and
+// need state between invocations. The state saved by the previous invocation
+// of the step is decoded into State, or left at its zero value on the first
+// one. When fn keeps the step running without setting a checkpoint, the state
+// it was handed is saved, so updating it in place is enough.
+func NewStatefulWorker[In, State any](name string, fn func(ctx context.Context, in In, state *State) (*types.StepResult, error)) *Worker {
+	invoke := func(ctx context.Context, workRequest *types.WorkRequest) (*types.StepResult, error) {
+		state := new(State)
+		if _, err := workRequest.DecodeCheckpoint(state); err != nil {
+			return nil, err
+		}
+		in, err := decodeInput[In](workRequest.InputWithoutCheckpoint())
+		if err != nil {
+			return nil, err
+		}
+		stepResult, err := fn(ctx, in, state)
+		if err != nil {
+			return nil, err
+		}
+		if stepResult != nil && stepResult.KeepRunning && stepResult.Checkpoint == nil {
+			stepResult.Checkpoint = state
+		}
+		return stepResult, nil
+	}
+
+	worker := NewWorker(invoke, name)
+	worker.invokeFunc = func(ctx context.Context, workRequest *types.WorkRequest) (interface{}, error) {
+		stepResult, err := invoke(ctx, workRequest)
+		if stepResult == nil {
+			return nil, err
+		}
+		return stepResult, err
+	}
+	return worker
+}
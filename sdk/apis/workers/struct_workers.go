@@ -0,0 +1,111 @@
+package workers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// WorkerMethodSuffix marks the methods of a struct that become workers without
+// being listed in StructOptions.Names.
+const WorkerMethodSuffix = "Worker"
+
+// StructOptions controls how the methods of a struct are turned into workers.
+type StructOptions struct {
+	// Namespace of the workers. Defaults to the worker default namespace.
+	Namespace string
+	// Names maps method names to worker names. Listing a method makes it a
+	// worker; mapping a method to "-" skips it.
+	Names map[string]string
+	// NameFunc derives the worker name of methods missing from Names from the
+	// method name without its Worker suffix. Defaults to the kebab-cased name,
+	// e.g. GetPersonWorker becomes get-person.
+	NameFunc func(methodName string) string
+	// MaxInProgress overrides the max in progress count per method name.
+	MaxInProgress map[string]int
+}
+
+// NewWorkersFromStruct creates a worker for every exported method of obj that is
+// listed in Names or whose name ends in WorkerMethodSuffix. Other methods, such
+// as setters or Close, are ignored. It fails when any of the selected methods
+// does not have a valid execution method signature. Pass a pointer to include
+// methods with pointer receivers.
+func NewWorkersFromStruct(obj interface{}, opts *StructOptions) ([]*Worker, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("worker struct cannot be nil")
+	}
+	if opts == nil {
+		opts = &StructOptions{}
+	}
+	nameFunc := opts.NameFunc
+	if nameFunc == nil {
+		nameFunc = KebabCase
+	}
+
+	value := reflect.ValueOf(obj)
+	valueType := value.Type()
+	found := make(map[string]bool)
+	var workers []*Worker
+	for i := 0; i < valueType.NumMethod(); i++ {
+		methodName := valueType.Method(i).Name
+		method := value.Method(i).Interface()
+		name, mapped := opts.Names[methodName]
+		if name == "-" {
+			found[methodName] = true
+			continue
+		}
+		baseName := strings.TrimSuffix(methodName, WorkerMethodSuffix)
+		if !mapped && (baseName == methodName || baseName == "") {
+			continue
+		}
+		if _, err := ParseMethodSignature(method); err != nil {
+			return nil, fmt.Errorf("method %s of %s cannot be used as a worker: %w", methodName, valueType, err)
+		}
+		found[methodName] = true
+		if !mapped {
+			name = nameFunc(baseName)
+		}
+
+		worker := NewWorker(method, name)
+		if opts.Namespace != "" {
+			worker.SetNamespace(opts.Namespace)
+		}
+		if maxInProgress, ok := opts.MaxInProgress[methodName]; ok {
+			worker.SetMaxInProgress(maxInProgress)
+		}
+		workers = append(workers, worker)
+	}
+
+	for methodName := range opts.Names {
+		if !found[methodName] {
+			return nil, fmt.Errorf("%s has no exported method %s", valueType, methodName)
+		}
+	}
+	for methodName := range opts.MaxInProgress {
+		if !found[methodName] {
+			return nil, fmt.Errorf("%s has no worker method %s", valueType, methodName)
+		}
+	}
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("%s has no methods listed in Names or ending in %s", valueType, WorkerMethodSuffix)
+	}
+	return workers, nil
+}
+
+// KebabCase converts a Go identifier such as GetHTTPStatus to get-http-status.
+func KebabCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				builder.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
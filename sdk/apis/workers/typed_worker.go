@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+// InvokeFunc executes a worker for a work request without going through reflection.
+type InvokeFunc func(ctx context.Context, workRequest *types.WorkRequest) (interface{}, error)
+
+// NewTypedWorker creates a worker whose execution method is checked at compile time.
+// The input is passed through as is when In is map[string]interface{} and decoded
+// into In as described by DecodeInput otherwise.
+func NewTypedWorker[In, Out any](name string, fn func(ctx context.Context, in In) (Out, error)) *Worker {
+	worker := NewWorker(fn, name)
+	worker.invokeFunc = func(ctx context.Context, workRequest *types.WorkRequest) (interface{}, error) {
+		in, err := decodeInput[In](workRequest.GetInputParam())
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, in)
+	}
+	return worker
+}
+
+func decodeInput[In any](input map[string]interface{}) (In, error) {
+	var in In
+	if typed, ok := any(input).(In); ok {
+		return typed, nil
+	}
+	value, err := DecodeInput(input, reflect.TypeOf(&in).Elem())
+	if err != nil {
+		return in, err
+	}
+	return value.Interface().(In), nil
+}
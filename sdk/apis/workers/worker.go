@@ -1,12 +1,25 @@
 package workers
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
 
 type Worker struct {
 	ExecutionMethod interface{}
 	Name            string
 	namespace       string
 	maxInProgress   int
+	stepTimeout     int64
+	invokeFunc      InvokeFunc
+	interceptors    []Interceptor
+	poolSize        int
+	poolQueueDepth  int
+	rateLimiter     *types.TokenBucket
+	retryPolicy     *RetryPolicy
+	outputNames     []string
 }
 
 func NewWorker(ExecutionMethod interface{}, Name string) *Worker {
@@ -27,6 +40,96 @@ func (worker *Worker) GetMaxInProgress() int {
 	return worker.maxInProgress
 }
 
+// SetStepTimeoutMillis overrides the client's StepTimeoutMillis for this worker.
+// Zero falls back to the client configuration.
+func (worker *Worker) SetStepTimeoutMillis(stepTimeoutMillis int64) {
+	if stepTimeoutMillis < 0 {
+		panic("Step timeout cannot be negative")
+	}
+	worker.stepTimeout = stepTimeoutMillis
+}
+
+func (worker *Worker) GetStepTimeoutMillis() int64 {
+	return worker.stepTimeout
+}
+
+// SetExecutionPool gives the worker its own pool of size goroutines that can
+// hold queueDepth additional requests, instead of sharing the client's pool.
+func (worker *Worker) SetExecutionPool(size int, queueDepth int) {
+	if size <= 0 {
+		panic("Execution pool size must be a positive integer")
+	}
+	if queueDepth < 0 {
+		panic("Execution pool queue depth cannot be negative")
+	}
+	worker.poolSize = size
+	worker.poolQueueDepth = queueDepth
+}
+
+// GetExecutionPoolSize returns the size of the worker's dedicated pool, or zero
+// when the worker runs on the shared pool.
+func (worker *Worker) GetExecutionPoolSize() int {
+	return worker.poolSize
+}
+
+func (worker *Worker) GetExecutionPoolQueueDepth() int {
+	return worker.poolQueueDepth
+}
+
+// SetRateLimit limits the worker to executions per window, allowing bursts of
+// up to burst executions. The client only polls for as much work as the limit
+// lets it start.
+func (worker *Worker) SetRateLimit(executions int, window time.Duration, burst int) {
+	if executions <= 0 {
+		panic("Rate limit executions must be a positive integer")
+	}
+	if window <= 0 {
+		panic("Rate limit window must be positive")
+	}
+	if burst < 0 {
+		panic("Rate limit burst cannot be negative")
+	}
+	worker.rateLimiter = types.NewTokenBucket(executions, window, burst)
+}
+
+func (worker *Worker) GetRateLimiter() *types.TokenBucket {
+	return worker.rateLimiter
+}
+
+// SetRetryPolicy retries failed executions locally before the step is reported
+// as failed.
+func (worker *Worker) SetRetryPolicy(retryPolicy *RetryPolicy) {
+	if retryPolicy != nil && retryPolicy.MaxAttempts < 1 {
+		panic("Retry policy max attempts must be a positive integer")
+	}
+	worker.retryPolicy = retryPolicy
+}
+
+func (worker *Worker) GetRetryPolicy() *RetryPolicy {
+	return worker.retryPolicy
+}
+
+// SetOutputNames names the return values of the execution method, so the step
+// output becomes an object keyed by these names instead of a list. A trailing
+// error return value is not named.
+func (worker *Worker) SetOutputNames(names ...string) {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if name == "" {
+			panic("Output names cannot be empty")
+		}
+		if seen[name] {
+			panic(fmt.Sprintf("Duplicate output name %s", name))
+		}
+		seen[name] = true
+	}
+	worker.outputNames = names
+}
+
+func (worker *Worker) GetOutputNames() []string {
+	return worker.outputNames
+}
+
 func (worker *Worker) SetNamespace(namespace string) {
 	worker.namespace = namespace
 }
@@ -36,12 +139,29 @@ func (worker *Worker) GetNamespace() string {
 
 func (w *Worker) SetExecutionMethod(ExecutionMethod interface{}) {
 	w.ExecutionMethod = ExecutionMethod
+	w.invokeFunc = nil
 }
 
 func (w *Worker) GetExecutionMethod() interface{} {
 	return w.ExecutionMethod
 }
 
+// GetInvokeFunc returns the pre-bound invocation of a typed worker, or nil when
+// the execution method has to be called through reflection.
+func (w *Worker) GetInvokeFunc() InvokeFunc {
+	return w.invokeFunc
+}
+
+// Use adds interceptors that run around this worker's executions, inside any
+// interceptors registered on the client.
+func (w *Worker) Use(interceptors ...Interceptor) {
+	w.interceptors = append(w.interceptors, interceptors...)
+}
+
+func (w *Worker) GetInterceptors() []Interceptor {
+	return w.interceptors
+}
+
 func (w *Worker) SetName(Name string) {
 	w.Name = Name
 }
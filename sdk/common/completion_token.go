@@ -0,0 +1,40 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// CompletionToken identifies a deferred step, so it can be completed later by
+// another process through CompleteStep or FailStep.
+type CompletionToken struct {
+	ProcessID       int64
+	StepID          int64
+	StepExecutionID int64
+}
+
+func NewCompletionToken(workRequest *WorkRequest) CompletionToken {
+	return CompletionToken{
+		ProcessID:       workRequest.GetProcessID(),
+		StepID:          workRequest.GetStepID(),
+		StepExecutionID: workRequest.GetStepExecutionID(),
+	}
+}
+
+// String encodes the token in a URL safe form that ParseCompletionToken reads.
+func (t CompletionToken) String() string {
+	raw := fmt.Sprintf("%d:%d:%d", t.ProcessID, t.StepID, t.StepExecutionID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func ParseCompletionToken(token string) (CompletionToken, error) {
+	var parsed CompletionToken
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return parsed, fmt.Errorf("invalid completion token: %w", err)
+	}
+	if _, err := fmt.Sscanf(string(raw), "%d:%d:%d", &parsed.ProcessID, &parsed.StepID, &parsed.StepExecutionID); err != nil {
+		return parsed, fmt.Errorf("invalid completion token: %w", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,26 @@
+package common
+
+import "fmt"
+
+// AttemptRecord describes one failed execution attempt of a step.
+type AttemptRecord struct {
+	Attempt        int    `json:"attempt"`
+	Error          string `json:"error"`
+	StartedAt      int64  `json:"startedAt"`
+	DurationMillis int64  `json:"durationMillis"`
+}
+
+// RetryError is returned when a step still failed after being retried. It
+// wraps the error of the last attempt.
+type RetryError struct {
+	Attempts []AttemptRecord
+	Last     error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", len(e.Attempts), e.Last)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Last
+}
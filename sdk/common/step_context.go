@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ProgressReporter submits the partial output of a step that is still running.
+type ProgressReporter func(partialOutput interface{}) error
+
+// StepContext carries the step being executed through the worker's call tree.
+// It travels inside the context.Context handed to the worker, so it can be read
+// from any goroutine the worker starts.
+type StepContext struct {
+	WorkRequest *WorkRequest
+	ProcessID   int64
+	StepRef     string
+	Attempt     int
+	Logger      *log.Logger
+	progress    ProgressReporter
+}
+
+type stepContextKey struct{}
+
+func NewStepContext(workRequest *WorkRequest) *StepContext {
+	prefix := fmt.Sprintf("[%s:%s process=%d step=%s] ",
+		workRequest.GetStepNamespace(), workRequest.GetStepName(), workRequest.GetProcessID(), workRequest.StepRef)
+	return &StepContext{
+		WorkRequest: workRequest,
+		ProcessID:   workRequest.GetProcessID(),
+		StepRef:     workRequest.StepRef,
+		Attempt:     1,
+		Logger:      log.New(log.Writer(), prefix, log.Flags()|log.Lmsgprefix),
+	}
+}
+
+// SetProgressReporter sets the function ReportProgress submits updates with.
+func (sc *StepContext) SetProgressReporter(reporter ProgressReporter) {
+	sc.progress = reporter
+}
+
+// ReportProgress submits partialOutput as the output of the still running step,
+// so the progress is visible before the step finishes. It fails once the step
+// has finished or when the step is not run by a client.
+func (sc *StepContext) ReportProgress(partialOutput interface{}) error {
+	if sc.progress == nil {
+		return errors.New("progress reporting is not available for this step")
+	}
+	return sc.progress(partialOutput)
+}
+
+// WithStepContext returns a copy of ctx that carries stepContext.
+func WithStepContext(ctx context.Context, stepContext *StepContext) context.Context {
+	return context.WithValue(ctx, stepContextKey{}, stepContext)
+}
+
+// StepContextFrom returns the StepContext stored in ctx, or nil when ctx does not
+// belong to a step execution.
+func StepContextFrom(ctx context.Context) *StepContext {
+	if ctx == nil {
+		return nil
+	}
+	stepContext, _ := ctx.Value(stepContextKey{}).(*StepContext)
+	return stepContext
+}
@@ -43,3 +43,19 @@ func (s *StepPollState) Release(count int) {
 		s.inProgress = 0
 	}
 }
+
+// AcquireUpTo acquires at most limit of the available permits and returns the
+// number acquired.
+func (s *StepPollState) AcquireUpTo(limit int) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	available := s.totalCount - s.inProgress
+	if limit < available {
+		available = limit
+	}
+	if available < 0 {
+		available = 0
+	}
+	s.inProgress += available
+	return available
+}
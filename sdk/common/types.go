@@ -0,0 +1,54 @@
+package common
+
+import (
+	"time"
+
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common/types"
+)
+
+// The step data types live in the types package so that the workers package can
+// use them without importing common, which itself depends on workers.
+
+type WorkRequest = types.WorkRequest
+
+type StepResult = types.StepResult
+
+type StepError = types.StepError
+
+type PanicError = types.PanicError
+
+type TokenBucket = types.TokenBucket
+
+const CheckpointKey = types.CheckpointKey
+
+func NewWorkRequest() *WorkRequest {
+	return types.NewWorkRequest()
+}
+
+func NewStepResult(result interface{}) *StepResult {
+	return types.NewStepResult(result)
+}
+
+// NewDeferredStepResult creates a result for a step that is completed
+// externally, for example after a human approval or a callback.
+func NewDeferredStepResult() *StepResult {
+	return types.NewDeferredStepResult()
+}
+
+func NewStepError(code string, message string) *StepError {
+	return types.NewStepError(code, message)
+}
+
+func NewPanicError(value interface{}, stack []byte) *PanicError {
+	return types.NewPanicError(value, stack)
+}
+
+func NewTokenBucket(executions int, window time.Duration, burst int) *TokenBucket {
+	return types.NewTokenBucket(executions, window, burst)
+}
+
+// UnmarshalPreservingNumbers decodes JSON like json.Unmarshal, but keeps numbers
+// as json.Number so that large integers survive the round trip.
+func UnmarshalPreservingNumbers(data []byte, v interface{}) error {
+	return types.UnmarshalPreservingNumbers(data, v)
+}
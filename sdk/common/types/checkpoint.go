@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CheckpointKey is the reserved key under which the checkpoint of a rescheduled
+// step is written to its output and read back from its input.
+const CheckpointKey = "__checkpoint"
+
+// HasCheckpoint reports whether the work request carries the checkpoint of a
+// previous invocation of the step.
+func (w *WorkRequest) HasCheckpoint() bool {
+	checkpoint, ok := w.InputParam[CheckpointKey]
+	return ok && checkpoint != nil
+}
+
+// DecodeCheckpoint decodes the checkpoint of the previous invocation into
+// target. It returns false and leaves target untouched when there is none.
+func (w *WorkRequest) DecodeCheckpoint(target interface{}) (bool, error) {
+	if !w.HasCheckpoint() {
+		return false, nil
+	}
+	jsonData, err := json.Marshal(w.InputParam[CheckpointKey])
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if err := UnmarshalPreservingNumbers(jsonData, target); err != nil {
+		return false, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return true, nil
+}
+
+// InputWithoutCheckpoint returns the input of the work request without the
+// reserved checkpoint entry.
+func (w *WorkRequest) InputWithoutCheckpoint() map[string]interface{} {
+	if _, ok := w.InputParam[CheckpointKey]; !ok {
+		return w.GetInputParam()
+	}
+	input := make(map[string]interface{}, len(w.InputParam))
+	for key, value := range w.InputParam {
+		if key != CheckpointKey {
+			input[key] = value
+		}
+	}
+	return input
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// UnmarshalPreservingNumbers works like json.Unmarshal but decodes numbers
+// stored in interface{} values as json.Number instead of float64, so large
+// integers such as 64-bit IDs keep their exact value.
+func UnmarshalPreservingNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxPanicStackLines = 40
+
+// PanicError is returned in place of a result when a worker panics.
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+// NewPanicError captures a recovered panic value together with the stack trace
+// of the panicking goroutine, trimmed to the frames below the panic call.
+func NewPanicError(value interface{}, stack []byte) *PanicError {
+	return &PanicError{
+		Value: value,
+		Stack: trimPanicStack(string(stack)),
+	}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("worker panicked: %v", e.Value)
+}
+
+func trimPanicStack(stack string) string {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	for i, line := range lines {
+		// Frames above panic() belong to the recovery itself.
+		if strings.HasPrefix(line, "panic(") && i+2 <= len(lines) {
+			lines = lines[i+2:]
+			break
+		}
+	}
+	if len(lines) > maxPanicStackLines {
+		lines = append(lines[:maxPanicStackLines], "...")
+	}
+	return strings.Join(lines, "\n")
+}
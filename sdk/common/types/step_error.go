@@ -0,0 +1,61 @@
+package types
+
+import "errors"
+
+// StepError is an error a worker can return to fail a step with a structured
+// output. The FAILED output then carries the code, type, message, details and
+// retryable flag under "error", so later steps can branch on them.
+type StepError struct {
+	Code      string
+	Type      string
+	Message   string
+	Details   map[string]interface{}
+	Retryable bool
+	Cause     error
+}
+
+func NewStepError(code string, message string) *StepError {
+	return &StepError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *StepError) Error() string {
+	message := e.Message
+	if e.Code != "" {
+		message = e.Code + ": " + message
+	}
+	if e.Cause != nil {
+		message += ": " + e.Cause.Error()
+	}
+	return message
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Cause
+}
+
+// Output returns the structured form of the error placed in the step output.
+func (e *StepError) Output() map[string]interface{} {
+	errorType := e.Type
+	if errorType == "" {
+		errorType = "StepError"
+	}
+	details := e.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	causes := make([]string, 0)
+	for cause := e.Cause; cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	return map[string]interface{}{
+		"code":      e.Code,
+		"type":      errorType,
+		"message":   e.Message,
+		"details":   details,
+		"retryable": e.Retryable,
+		"causes":    causes,
+	}
+}
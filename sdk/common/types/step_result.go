@@ -0,0 +1,27 @@
+package types
+
+type StepResult struct {
+	Result                 interface{}
+	KeepRunning            bool
+	RescheduleAfterSeconds int
+	// Checkpoint is sent along with a rescheduled step and handed back to the
+	// worker on its next invocation. See WorkRequest.DecodeCheckpoint.
+	Checkpoint interface{}
+	// Deferred leaves the step RUNNING without submitting a result. The step is
+	// finished later with CompleteStep or FailStep.
+	Deferred bool
+}
+
+func NewStepResult(result interface{}) *StepResult {
+	return &StepResult{Result: result, KeepRunning: false, RescheduleAfterSeconds: 0}
+}
+
+// NewDeferredStepResult creates a result for a step that is completed
+// externally, for example after a human approval or a callback.
+func NewDeferredStepResult() *StepResult {
+	return &StepResult{Deferred: true}
+}
+
+func (sr *StepResult) GetResult() interface{} {
+	return sr.Result
+}
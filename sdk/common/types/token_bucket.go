@@ -0,0 +1,76 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket limits how many executions can be started over time. Tokens are
+// refilled continuously at executions per window, up to burst.
+type TokenBucket struct {
+	capacity   float64
+	perSecond  float64
+	tokens     float64
+	lastRefill time.Time
+	lock       sync.Mutex
+}
+
+// NewTokenBucket creates a full bucket allowing executions per window with the
+// given burst. A burst below one defaults to executions.
+func NewTokenBucket(executions int, window time.Duration, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = executions
+	}
+	return &TokenBucket{
+		capacity:   float64(burst),
+		perSecond:  float64(executions) / window.Seconds(),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+func (b *TokenBucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.perSecond
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastRefill = now
+}
+
+// Available returns the number of whole tokens currently in the bucket.
+func (b *TokenBucket) Available() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill()
+	return int(b.tokens)
+}
+
+// TryAcquire takes at most limit whole tokens and returns the number taken.
+func (b *TokenBucket) TryAcquire(limit int) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill()
+	taken := int(b.tokens)
+	if limit < taken {
+		taken = limit
+	}
+	if taken < 0 {
+		taken = 0
+	}
+	b.tokens -= float64(taken)
+	return taken
+}
+
+// Return gives back tokens that were acquired but not used.
+func (b *TokenBucket) Return(count int) {
+	if count <= 0 {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tokens += float64(count)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -1,4 +1,7 @@
-package common
+// Package types holds the step data types shared by the workers package and
+// common. They are re-exported from common, which is where callers should use
+// them from.
+package types
 
 type WorkRequest struct {
 	ProcessID       int64                  `json:"processId,omitempty"`
@@ -3,10 +3,18 @@ package common
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 )
 
+// Error codes used in the output of failures that are not a StepError.
+const (
+	ErrorCodeWorkerError  = "WORKER_ERROR"
+	ErrorCodeWorkerPanic  = "WORKER_PANIC"
+	ErrorCodeStepTimedOut = "STEP_TIMED_OUT"
+)
+
 type WorkResponseBuilder struct{}
 
 func NewWorkResponseBuilder() *WorkResponseBuilder {
@@ -19,7 +27,7 @@ func (b *WorkResponseBuilder) resultToMap(obj interface{}) map[string]interface{
 	}
 
 	switch v := obj.(type) {
-	case bool, string, int, int64, float64:
+	case bool, string, int, int64, float64, json.Number:
 		return map[string]interface{}{"result": v}
 
 	case map[string]interface{}:
@@ -71,7 +79,7 @@ func (b *WorkResponseBuilder) resultToMap(obj interface{}) map[string]interface{
 		}
 
 		var result map[string]interface{}
-		if err := json.Unmarshal(jsonData, &result); err != nil {
+		if err := UnmarshalPreservingNumbers(jsonData, &result); err != nil {
 			log.Printf("Error unmarshaling JSON: %v", err)
 			return map[string]interface{}{"error": "failed to unmarshal"}
 		}
@@ -83,15 +91,22 @@ func (b *WorkResponseBuilder) resultToMap(obj interface{}) map[string]interface{
 func (b *WorkResponseBuilder) FailResponse(workRequest *WorkRequest, context error) *WorkResponse {
 	actualCause := b.tryPeelIrrelevantExceptions(context)
 
-	var innerError interface{}
-	errMsg := actualCause.Error()
-
-	if json.Unmarshal([]byte(errMsg), &innerError) != nil {
-		innerError = errMsg
+	var retryErr *RetryError
+	if errors.As(actualCause, &retryErr) {
+		actualCause = retryErr.Last
 	}
 
 	output := map[string]interface{}{
-		"error": innerError,
+		"error": toStepError(actualCause).Output(),
+	}
+
+	var panicErr *PanicError
+	if errors.As(context, &panicErr) {
+		output["panic"] = fmt.Sprintf("%v", panicErr.Value)
+		output["stackTrace"] = panicErr.Stack
+	}
+	if retryErr != nil {
+		output["attempts"] = retryErr.Attempts
 	}
 
 	workResponse := NewWorkResponse()
@@ -109,6 +124,35 @@ func (b *WorkResponseBuilder) FailResponse(workRequest *WorkRequest, context err
 	return workResponse
 }
 
+// toStepError describes any worker failure as a StepError, so that the output of
+// every failed step has the same structure. Plain errors whose message is a JSON
+// object keep that object as their details.
+func toStepError(err error) *StepError {
+	var stepErr *StepError
+	if errors.As(err, &stepErr) {
+		return stepErr
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return &StepError{
+			Code:    ErrorCodeWorkerPanic,
+			Type:    "PanicError",
+			Message: fmt.Sprintf("%v", panicErr.Value),
+		}
+	}
+	var details map[string]interface{}
+	if json.Unmarshal([]byte(err.Error()), &details) != nil {
+		details = nil
+	}
+	return &StepError{
+		Code:    ErrorCodeWorkerError,
+		Type:    "Error",
+		Message: err.Error(),
+		Details: details,
+		Cause:   errors.Unwrap(err),
+	}
+}
+
 func (b *WorkResponseBuilder) tryPeelIrrelevantExceptions(context error) error {
 	actualCause := context
 
@@ -147,6 +191,14 @@ func (b *WorkResponseBuilder) SuccessResponse(workRequest *WorkRequest, stepResu
 
 func (b *WorkResponseBuilder) RunningResponse(workRequest *WorkRequest, stepResult *StepResult) *WorkResponse {
 	output := b.resultToMap(stepResult.GetResult())
+	if stepResult.Checkpoint != nil {
+		withCheckpoint := make(map[string]interface{}, len(output)+1)
+		for key, value := range output {
+			withCheckpoint[key] = value
+		}
+		withCheckpoint[CheckpointKey] = stepResult.Checkpoint
+		output = withCheckpoint
+	}
 	workResponse := NewWorkResponse()
 	workResponse.SetProcessID(workRequest.GetProcessID())
 	workResponse.SetStepID(workRequest.GetStepID())
@@ -161,3 +213,27 @@ func (b *WorkResponseBuilder) RunningResponse(workRequest *WorkRequest, stepResu
 	workResponse.SetRescheduleAfterSeconds(stepResult.RescheduleAfterSeconds)
 	return workResponse
 }
+
+func (b *WorkResponseBuilder) TimedOutResponse(workRequest *WorkRequest, timeout time.Duration) *WorkResponse {
+	timeoutErr := &StepError{
+		Code:    ErrorCodeStepTimedOut,
+		Type:    "TimeoutError",
+		Message: fmt.Sprintf("step timed out after %d ms", timeout.Milliseconds()),
+		Details: map[string]interface{}{"timeoutMillis": timeout.Milliseconds()},
+	}
+	output := map[string]interface{}{
+		"error": timeoutErr.Output(),
+	}
+	workResponse := NewWorkResponse()
+	workResponse.SetProcessID(workRequest.GetProcessID())
+	workResponse.SetStepID(workRequest.GetStepID())
+	if workRequest.StepExecutionID == 0 {
+		workResponse.StepExecutionID = 0
+	} else {
+		workResponse.SetStepExecutionID(workRequest.GetStepExecutionID())
+	}
+	workResponse.SetOutput(output)
+	workResponse.SetStartedAt(time.Now().UnixMilli())
+	workResponse.SetStatus(StepStatusTimedOut)
+	return workResponse
+}
@@ -5,6 +5,9 @@ type WorkResponseTracker struct {
 	RetryCount    int
 	QueuedTime    int64
 	StepPollState *StepPollState
+	// OnResolved, when set, is called once the response has been acknowledged
+	// by the server or given up on.
+	OnResolved func()
 }
 
 func NewWorkResponseTracker(workResponse *WorkResponse) *WorkResponseTracker {
@@ -23,6 +23,13 @@ type ClientConfig struct {
 	MaxSubmitAttempts               int64
 	SubmitClientSleepIntervalMillis int64
 	EnableResultsSubmission         bool
+	PriorityAgingMillis             int64
+	MaxPollDelayMillis              int64
+	LongPollWaitMillis              int64
+	RegistrationRenewIntervalSecs   int64
+	DedupCacheSize                  int
+	DedupTTLSeconds                 int64
+	reregistrationStatusCodes       []int
 }
 
 func NewClientConfig() *ClientConfig {
@@ -58,6 +65,12 @@ func NewClientConfig() *ClientConfig {
 		MaxSubmitAttempts:               maxSubmitAttempts,
 		SubmitClientSleepIntervalMillis: 100,
 		EnableResultsSubmission:         true,
+		PriorityAgingMillis:             1000,
+		MaxPollDelayMillis:              2000,
+		RegistrationRenewIntervalSecs:   60,
+		DedupCacheSize:                  10000,
+		DedupTTLSeconds:                 600,
+		reregistrationStatusCodes:       []int{404, 410},
 	}
 }
 
@@ -65,6 +78,12 @@ func (c *ClientConfig) PermanentErrorKeywords() []string {
 	return c.permanentErrorKeywords
 }
 
+// ReregistrationStatusCodes lists the poll response status codes that indicate
+// the server no longer knows about this client or its workers.
+func (c *ClientConfig) ReregistrationStatusCodes() []int {
+	return c.reregistrationStatusCodes
+}
+
 func (c *ClientConfig) HasToken() bool {
 	return c.AuthToken != "" // No need for nil check as it's a string now
 }
@@ -94,6 +113,14 @@ func (c *ClientConfig) GetSubmitClientSleepIntervalMillis() int64 {
 func (c *ClientConfig) IsEnableResultsSubmission() bool {
 	return c.EnableResultsSubmission
 }
+func (c *ClientConfig) GetPriorityAgingMillis() int64 { return c.PriorityAgingMillis }
+func (c *ClientConfig) GetMaxPollDelayMillis() int64  { return c.MaxPollDelayMillis }
+func (c *ClientConfig) GetLongPollWaitMillis() int64  { return c.LongPollWaitMillis }
+func (c *ClientConfig) GetRegistrationRenewIntervalSecs() int64 {
+	return c.RegistrationRenewIntervalSecs
+}
+func (c *ClientConfig) GetDedupCacheSize() int    { return c.DedupCacheSize }
+func (c *ClientConfig) GetDedupTTLSeconds() int64 { return c.DedupTTLSeconds }
 
 func (c *ClientConfig) SetNamespace(namespace string) {
 	if namespace == "" {
@@ -142,9 +169,11 @@ func (c *ClientConfig) SetSubmitClientPollTimeoutSeconds(submitClientPollTimeout
 	c.SubmitClientPollTimeoutSeconds = submitClientPollTimeoutSecs
 }
 
+// SetStepTimeoutMillis limits how long a worker may run a single step. The
+// default is 5000; zero means no limit.
 func (c *ClientConfig) SetStepTimeoutMillis(stepTimeoutMillis int64) {
-	if stepTimeoutMillis <= 0 {
-		panic("Step timeout must be a positive integer")
+	if stepTimeoutMillis < 0 {
+		panic("Step timeout cannot be negative")
 	}
 	c.StepTimeoutMillis = stepTimeoutMillis
 }
@@ -213,3 +242,60 @@ func (c *ClientConfig) SetSubmitClientSleepIntervalMillis(sleepIntervalMillis in
 func (c *ClientConfig) SetEnableResultsSubmission(enabled bool) {
 	c.EnableResultsSubmission = enabled
 }
+
+// SetPriorityAgingMillis sets how long a queued work request has to wait to gain
+// one level of priority. Zero disables aging.
+func (c *ClientConfig) SetPriorityAgingMillis(priorityAgingMillis int64) {
+	if priorityAgingMillis < 0 {
+		panic("Priority aging interval cannot be negative")
+	}
+	c.PriorityAgingMillis = priorityAgingMillis
+}
+
+// SetMaxPollDelayMillis sets the upper bound the poll delay backs off to while
+// polls come back empty. A value not above DelayMillis disables the backoff.
+func (c *ClientConfig) SetMaxPollDelayMillis(maxPollDelayMillis int64) {
+	if maxPollDelayMillis < 0 {
+		panic("Max poll delay cannot be negative")
+	}
+	c.MaxPollDelayMillis = maxPollDelayMillis
+}
+
+// SetLongPollWaitMillis asks the server to hold a poll for up to the given time
+// until work is available. Zero disables long polling.
+func (c *ClientConfig) SetLongPollWaitMillis(longPollWaitMillis int64) {
+	if longPollWaitMillis < 0 {
+		panic("Long poll wait cannot be negative")
+	}
+	if longPollWaitMillis >= 30000 {
+		panic("Long poll wait must be shorter than the 30 second request timeout")
+	}
+	c.LongPollWaitMillis = longPollWaitMillis
+}
+
+// SetRegistrationRenewIntervalSecs sets how often the worker registration is
+// renewed while the client is running. Zero disables the periodic renewal.
+func (c *ClientConfig) SetRegistrationRenewIntervalSecs(registrationRenewIntervalSecs int64) {
+	if registrationRenewIntervalSecs < 0 {
+		panic("Registration renew interval cannot be negative")
+	}
+	c.RegistrationRenewIntervalSecs = registrationRenewIntervalSecs
+}
+
+// SetDedupCacheSize sets how many recently received step executions are
+// remembered to skip duplicate deliveries. Zero disables duplicate detection.
+func (c *ClientConfig) SetDedupCacheSize(dedupCacheSize int) {
+	if dedupCacheSize < 0 {
+		panic("Dedup cache size cannot be negative")
+	}
+	c.DedupCacheSize = dedupCacheSize
+}
+
+// SetDedupTTLSeconds limits how long a finished step execution is remembered
+// while its result waits to be acknowledged.
+func (c *ClientConfig) SetDedupTTLSeconds(dedupTTLSeconds int64) {
+	if dedupTTLSeconds <= 0 {
+		panic("Dedup TTL must be a positive integer")
+	}
+	c.DedupTTLSeconds = dedupTTLSeconds
+}
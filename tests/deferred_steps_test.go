@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apis "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/main"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
+)
+
+func TestCompletionToken_RoundTrip(t *testing.T) {
+	workRequest := newTestWorkRequest(nil)
+	workRequest.ProcessID = 101
+	workRequest.StepID = 202
+	workRequest.StepExecutionID = 303
+
+	token := common.NewCompletionToken(workRequest)
+	parsed, err := common.ParseCompletionToken(token.String())
+	assert.NoError(t, err)
+	assert.Equal(t, token, parsed)
+
+	_, err = common.ParseCompletionToken("not-a-token")
+	assert.Error(t, err)
+}
+
+func TestCompleteAndFailStep(t *testing.T) {
+	os.Setenv("DISABLE_SUBMIT_CLIENT", "true")
+	defer os.Unsetenv("DISABLE_SUBMIT_CLIENT")
+
+	var received []*common.WorkResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/clients/bulkResults", r.URL.Path)
+		var batch []*common.WorkResponse
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		received = append(received, batch...)
+
+		result := map[string]interface{}{}
+		if batch[0].GetStepID() == 2 {
+			result["ErrorMessage"] = "step is not in RUNNING state"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{fmt.Sprintf("%d", batch[0].GetStepID()): result})
+	}))
+	defer server.Close()
+
+	config := configs.NewClientConfig()
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	config.SetBaseURL(server.URL)
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.CompleteStep(10, 1, 100, map[string]interface{}{"approved": true}))
+	err = client.FailStep(10, 2, 200, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not in RUNNING state")
+
+	assert.Len(t, received, 2)
+	assert.Equal(t, common.StepStatusCompleted, received[0].GetStatus())
+	assert.Equal(t, true, received[0].GetOutput()["approved"])
+	assert.Equal(t, int64(100), received[0].GetStepExecutionID())
+	assert.Equal(t, common.StepStatusFailed, received[1].GetStatus())
+}
+
+func TestCompleteStepWithToken(t *testing.T) {
+	os.Setenv("DISABLE_SUBMIT_CLIENT", "true")
+	defer os.Unsetenv("DISABLE_SUBMIT_CLIENT")
+
+	var received []*common.WorkResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/clients/bulkResults", r.URL.Path)
+		var batch []*common.WorkResponse
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		received = append(received, batch...)
+		json.NewEncoder(w).Encode(map[string]interface{}{fmt.Sprintf("%d", batch[0].GetStepID()): map[string]interface{}{}})
+	}))
+	defer server.Close()
+
+	config := configs.NewClientConfig()
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	config.SetBaseURL(server.URL)
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	token := common.CompletionToken{ProcessID: 10, StepID: 1, StepExecutionID: 100}
+	assert.NoError(t, client.CompleteStepWithToken(token, map[string]interface{}{"approved": true}))
+	assert.NoError(t, client.FailStepWithToken(common.CompletionToken{ProcessID: 10, StepID: 2, StepExecutionID: 200}, nil))
+
+	assert.Len(t, received, 2)
+	assert.Equal(t, common.StepStatusCompleted, received[0].GetStatus())
+	assert.Equal(t, int64(10), received[0].GetProcessID())
+	assert.Equal(t, int64(1), received[0].GetStepID())
+	assert.Equal(t, int64(100), received[0].GetStepExecutionID())
+	assert.Equal(t, common.StepStatusFailed, received[1].GetStatus())
+	assert.Empty(t, client.GetRuntimeStats().DeferredSteps)
+}
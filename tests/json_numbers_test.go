@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	runner "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/runner"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+type accountInput struct {
+	AccountID int64       `json:"accountId"`
+	Amount    interface{} `json:"amount"`
+}
+
+func newLargeNumberWorkRequest(t *testing.T) *common.WorkRequest {
+	var workRequests []common.WorkRequest
+	body := []byte(`[{"stepName":"test-worker","inputParam":{"accountId":9007199254740993,"amount":12345678901234567.25}}]`)
+	assert.NoError(t, common.UnmarshalPreservingNumbers(body, &workRequests))
+	return &workRequests[0]
+}
+
+func TestRunWorker_PreservesLargeNumbers(t *testing.T) {
+	worker := workers.NewWorker(func(in accountInput) accountInput { return in }, "typed")
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newLargeNumberWorkRequest(t))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), result.GetResult().(accountInput).AccountID)
+	assert.Equal(t, json.Number("12345678901234567.25"), result.GetResult().(accountInput).Amount)
+
+	worker = workers.NewWorker(func(in map[string]interface{}) map[string]interface{} { return in }, "map")
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), worker, newLargeNumberWorkRequest(t))
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("9007199254740993"), result.GetResult().(map[string]interface{})["accountId"])
+
+	typed := workers.NewTypedWorker("generic", func(ctx context.Context, in accountInput) (int64, error) {
+		return in.AccountID, nil
+	})
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), typed, newLargeNumberWorkRequest(t))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), result.GetResult())
+}
+
+func TestSuccessResponse_PreservesLargeNumbers(t *testing.T) {
+	stepResult := common.NewStepResult(accountInput{AccountID: 9007199254740993})
+	workResponse := common.NewWorkResponseBuilder().SuccessResponse(newTestWorkRequest(nil), stepResult)
+
+	data, err := json.Marshal(workResponse.GetOutput())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"accountId":9007199254740993,"amount":null}`, string(data))
+}
+
+func TestSuccessResponse_DecodedNumberResult(t *testing.T) {
+	worker := workers.NewWorker(func(in map[string]interface{}) interface{} { return in["accountId"] }, "passthrough")
+	workRequest := newLargeNumberWorkRequest(t)
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+
+	workResponse := common.NewWorkResponseBuilder().SuccessResponse(workRequest, result)
+	assert.Equal(t, map[string]interface{}{"result": json.Number("9007199254740993")}, workResponse.GetOutput())
+}
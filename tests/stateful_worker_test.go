@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	runner "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/runner"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+type pollState struct {
+	Iterations int   `json:"iterations"`
+	JobID      int64 `json:"jobId"`
+}
+
+func TestStatefulWorker_CarriesCheckpoint(t *testing.T) {
+	var inputs []map[string]interface{}
+	worker := workers.NewStatefulWorker("poll-job", func(ctx context.Context, in map[string]interface{}, state *pollState) (*common.StepResult, error) {
+		inputs = append(inputs, in)
+		if state.Iterations == 2 {
+			return common.NewStepResult("done"), nil
+		}
+		state.Iterations++
+		state.JobID = 9007199254740993
+		stepResult := common.NewStepResult("waiting")
+		stepResult.KeepRunning = true
+		stepResult.RescheduleAfterSeconds = 1
+		return stepResult, nil
+	})
+	builder := common.NewWorkResponseBuilder()
+
+	workRequest := newTestWorkRequest(map[string]interface{}{"url": "http://jobs"})
+	for i := 0; i < 2; i++ {
+		result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+		assert.NoError(t, err)
+		assert.True(t, result.KeepRunning)
+
+		workResponse := builder.RunningResponse(workRequest, result)
+		assert.Equal(t, "waiting", workResponse.GetOutput()["result"])
+		assert.NotNil(t, workResponse.GetOutput()[common.CheckpointKey])
+
+		// The rescheduled step receives its checkpoint back in the input.
+		workRequest = newTestWorkRequest(map[string]interface{}{
+			"url":                "http://jobs",
+			common.CheckpointKey: workResponse.GetOutput()[common.CheckpointKey],
+		})
+	}
+
+	var state pollState
+	found, err := workRequest.DecodeCheckpoint(&state)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, pollState{Iterations: 2, JobID: 9007199254740993}, state)
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+	assert.False(t, result.KeepRunning)
+	assert.Equal(t, "done", result.GetResult())
+	for _, in := range inputs {
+		assert.Equal(t, map[string]interface{}{"url": "http://jobs"}, in)
+	}
+}
+
+func TestRunningResponse_DoesNotModifyResult(t *testing.T) {
+	output := map[string]interface{}{"status": "pending"}
+	stepResult := common.NewStepResult(output)
+	stepResult.Checkpoint = map[string]interface{}{"cursor": "abc"}
+
+	workResponse := common.NewWorkResponseBuilder().RunningResponse(newTestWorkRequest(nil), stepResult)
+	assert.Equal(t, map[string]interface{}{"cursor": "abc"}, workResponse.GetOutput()[common.CheckpointKey])
+	assert.NotContains(t, output, common.CheckpointKey)
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apis "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/main"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/configs"
+)
+
+type orderService struct {
+	prefix string
+	db     map[string]interface{}
+}
+
+func (s *orderService) CreateOrder(data map[string]interface{}) string {
+	return s.prefix + "created"
+}
+
+func (s *orderService) CancelOrderWorker(ctx context.Context, data map[string]interface{}) (string, error) {
+	return s.prefix + "cancelled", nil
+}
+
+func (s *orderService) GetHTTPStatusWorker(data map[string]interface{}) int {
+	return 200
+}
+
+func (s *orderService) SetDB(db map[string]interface{}) {
+	s.db = db
+}
+
+func (s *orderService) Merge(a, b map[string]interface{}) error {
+	return nil
+}
+
+func (s *orderService) Close() error {
+	return nil
+}
+
+type brokenService struct{}
+
+func (s *brokenService) ValidWorker(data map[string]interface{}) string {
+	return "ok"
+}
+
+func (s *brokenService) MergeWorker(a, b map[string]interface{}) error {
+	return nil
+}
+
+func TestNewWorkersFromStruct(t *testing.T) {
+	structWorkers, err := workers.NewWorkersFromStruct(&orderService{prefix: "order "}, &workers.StructOptions{
+		Namespace:     "orders",
+		Names:         map[string]string{"CreateOrder": "create_order", "GetHTTPStatusWorker": "-"},
+		MaxInProgress: map[string]int{"CancelOrderWorker": 5},
+	})
+	assert.NoError(t, err)
+
+	byName := make(map[string]*workers.Worker)
+	for _, worker := range structWorkers {
+		assert.Equal(t, "orders", worker.GetNamespace())
+		byName[worker.GetName()] = worker
+	}
+	// SetDB, Merge and Close are neither listed nor suffixed.
+	assert.Len(t, byName, 2)
+	assert.Contains(t, byName, "create_order")
+	assert.Equal(t, 5, byName["cancel-order"].GetMaxInProgress())
+	assert.Equal(t, 100, byName["create_order"].GetMaxInProgress())
+
+	method := byName["create_order"].GetExecutionMethod().(func(map[string]interface{}) string)
+	assert.Equal(t, "order created", method(nil))
+}
+
+func TestNewWorkersFromStruct_InvalidMappings(t *testing.T) {
+	_, err := workers.NewWorkersFromStruct(&orderService{}, &workers.StructOptions{
+		Names: map[string]string{"Merge": "merge"},
+	})
+	assert.Error(t, err)
+
+	structWorkers, err := workers.NewWorkersFromStruct(&orderService{}, &workers.StructOptions{
+		Names: map[string]string{"Close": "close"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, structWorkers, 3)
+
+	_, err = workers.NewWorkersFromStruct(&orderService{}, &workers.StructOptions{
+		MaxInProgress: map[string]int{"DeleteOrder": 1},
+	})
+	assert.Error(t, err)
+
+	_, err = workers.NewWorkersFromStruct(&brokenService{}, nil)
+	assert.Error(t, err, "suffixed methods must have a valid signature")
+
+	_, err = workers.NewWorkersFromStruct(struct{}{}, nil)
+	assert.Error(t, err)
+}
+
+func TestKebabCase(t *testing.T) {
+	assert.Equal(t, "sum", workers.KebabCase("Sum"))
+	assert.Equal(t, "get-person", workers.KebabCase("GetPerson"))
+	assert.Equal(t, "get-http-status", workers.KebabCase("GetHTTPStatus"))
+	assert.Equal(t, "to-v2-format", workers.KebabCase("ToV2Format"))
+}
+
+func TestRegisterWorkerStruct(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.RegisterWorkerStruct(&orderService{}, nil))
+	assert.Len(t, client.Workers, 2)
+	assert.Error(t, client.RegisterWorkerStruct(&orderService{}, nil))
+}
+
+func TestRegisterWorkerStruct_RegistersAllOrNothing(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.AddWorker(workers.NewWorker(func(data map[string]interface{}) int { return 0 }, "get-http-status")))
+	// cancel-order would be new, but get-http-status is taken.
+	assert.Error(t, client.RegisterWorkerStruct(&orderService{}, nil))
+	assert.Len(t, client.Workers, 1)
+}
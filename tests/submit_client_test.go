@@ -1,9 +1,16 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	apisHttp "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/http"
@@ -113,3 +120,105 @@ func TestClose_Idempotent(t *testing.T) {
 	client.Stop()
 	client.Stop()
 }
+
+func TestDrain_ReturnsUndeliveredOnDeadline(t *testing.T) {
+	os.Setenv("DISABLE_SUBMIT_CLIENT", "true")
+	defer os.Unsetenv("DISABLE_SUBMIT_CLIENT")
+	config := configs.NewClientConfig()
+	config.SetClientID("test-client")
+	factory := apisHttp.NewHttpRequestFactory(config)
+	client := apisSubmit.NewSubmitClient(factory, config)
+	workResponse := common.NewWorkResponse()
+	workResponse.SetStepID(42)
+	client.Submit(workResponse, common.NewStepPollState(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	undelivered := client.Drain(ctx)
+	assert.Len(t, undelivered, 1)
+	assert.Equal(t, int64(42), undelivered[0].GetStepID())
+}
+
+func TestSubmitProgress_DroppedOnceResultIsQueued(t *testing.T) {
+	var lock sync.Mutex
+	var received []*common.WorkResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/clients/bulkResults", r.URL.Path)
+		var batch []*common.WorkResponse
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		lock.Lock()
+		received = append(received, batch...)
+		lock.Unlock()
+		results := map[string]interface{}{}
+		for _, workResponse := range batch {
+			results[fmt.Sprintf("%d", workResponse.GetStepID())] = map[string]interface{}{}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	config := configs.NewClientConfig()
+	config.SetClientID("test-client")
+	config.SetBaseURL(server.URL)
+	config.SetSubmitClientSleepIntervalMillis(500)
+	client := apisSubmit.NewSubmitClient(apisHttp.NewHttpRequestFactory(config), config)
+	defer client.Stop()
+	// Let the submitters find the queues empty, so everything below is queued
+	// before the next batch is taken.
+	time.Sleep(50 * time.Millisecond)
+
+	builder := common.NewWorkResponseBuilder()
+	running := newTestWorkRequest(nil)
+	running.StepID = 5
+	assert.NoError(t, client.SubmitProgress(builder.RunningResponse(running, common.NewStepResult(map[string]interface{}{"batchesDone": 1}))))
+
+	finished := newTestWorkRequest(nil)
+	finished.StepID = 6
+	assert.NoError(t, client.SubmitProgress(builder.RunningResponse(finished, common.NewStepResult(map[string]interface{}{"batchesDone": 2}))))
+	state := common.NewStepPollState(1)
+	state.AcquireMaxAvailable()
+	client.Submit(builder.SuccessResponse(finished, common.NewStepResult("done")), state)
+
+	assert.Eventually(t, func() bool { return client.GetSubmitTrackerSize() == 0 }, 5*time.Second, 20*time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Len(t, received, 2)
+	assert.Equal(t, int64(5), received[0].GetStepID())
+	assert.Equal(t, common.StepStatusRunning, received[0].GetStatus())
+	assert.Equal(t, int64(6), received[1].GetStepID())
+	assert.Equal(t, common.StepStatusCompleted, received[1].GetStatus())
+}
+
+func TestSubmitWithCallback_CalledOnceAcknowledged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []*common.WorkResponse
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		results := map[string]interface{}{}
+		for _, workResponse := range batch {
+			results[fmt.Sprintf("%d", workResponse.GetStepID())] = map[string]interface{}{}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	config := configs.NewClientConfig()
+	config.SetClientID("test-client")
+	config.SetBaseURL(server.URL)
+	client := apisSubmit.NewSubmitClient(apisHttp.NewHttpRequestFactory(config), config)
+	defer client.Stop()
+
+	workResponse := common.NewWorkResponse()
+	workResponse.SetStepID(5)
+	state := common.NewStepPollState(1)
+	assert.Equal(t, 1, state.AcquireMaxAvailable())
+	resolved := make(chan struct{})
+	client.SubmitWithCallback(workResponse, state, func() { close(resolved) })
+
+	select {
+	case <-resolved:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not called after the result was acknowledged")
+	}
+	assert.Equal(t, 1, state.MaxAvailable())
+	assert.Equal(t, 0, client.GetSubmitTrackerSize())
+}
@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestTokenBucket_BurstAndReturn(t *testing.T) {
+	bucket := common.NewTokenBucket(10, time.Hour, 3)
+
+	assert.Equal(t, 3, bucket.Available())
+	assert.Equal(t, 2, bucket.TryAcquire(2))
+	assert.Equal(t, 1, bucket.TryAcquire(5))
+	assert.Equal(t, 0, bucket.TryAcquire(5))
+
+	bucket.Return(2)
+	assert.Equal(t, 2, bucket.Available())
+
+	bucket.Return(10)
+	assert.Equal(t, 3, bucket.Available())
+}
+
+func TestTokenBucket_Refills(t *testing.T) {
+	bucket := common.NewTokenBucket(100, time.Second, 1)
+
+	assert.Equal(t, 1, bucket.TryAcquire(1))
+	assert.Equal(t, 0, bucket.TryAcquire(1))
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, bucket.TryAcquire(1))
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -96,7 +97,21 @@ func TestRegisterWorker_WrongSignature(t *testing.T) {
 	worker := workers.NewWorker(func(a, b interface{}) interface{} { return a }, "worker1")
 	err = client.RegisterWorker(worker)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "must have exactly one parameter")
+	assert.Contains(t, err.Error(), "must have at most one input parameter")
+}
+
+func TestRegisterWorker_ContextSignature(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	worker := workers.NewWorker(func(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+		return input, nil
+	}, "worker1")
+	err = client.RegisterWorker(worker)
+	assert.NoError(t, err)
 }
 
 func TestStart_NoWorkers(t *testing.T) {
@@ -176,3 +191,88 @@ func TestProcessOperations(t *testing.T) {
 	_, err = client.SearchProcessExecutions(searchParams)
 	assert.NotNil(t, err)
 }
+
+func TestShutdown_NotStarted(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	summary, err := client.Shutdown(ctx)
+	assert.NoError(t, err)
+	assert.True(t, summary.Clean())
+
+	select {
+	case <-client.DoneChan():
+	default:
+		t.Error("done channel should be closed after Shutdown()")
+	}
+}
+
+func TestAddAndRemoveWorker(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	worker := workers.NewWorker(func(input interface{}) interface{} { return input }, "worker1")
+	assert.NoError(t, client.AddWorker(worker))
+	assert.Len(t, client.Workers, 1)
+
+	assert.NoError(t, client.RemoveWorker("default", "worker1"))
+	assert.Empty(t, client.Workers)
+
+	err = client.RemoveWorker("default", "worker1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not registered")
+
+	assert.NoError(t, client.AddWorker(worker))
+	assert.Len(t, client.Workers, 1)
+}
+
+func TestPauseAndResumeWorker(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	worker := workers.NewWorker(func(input interface{}) interface{} { return input }, "worker1")
+	assert.NoError(t, client.AddWorker(worker))
+
+	assert.NoError(t, client.PauseWorker("default", "worker1"))
+	assert.True(t, client.IsWorkerPaused("default", "worker1"))
+	stats := client.GetRuntimeStats()
+	assert.Len(t, stats.Workers, 1)
+	assert.True(t, stats.Workers[0].Paused)
+	assert.Equal(t, 100, stats.Workers[0].Available)
+
+	assert.NoError(t, client.ResumeWorker("default", "worker1"))
+	assert.False(t, client.IsWorkerPaused("default", "worker1"))
+	assert.False(t, client.GetRuntimeStats().Workers[0].Paused)
+
+	assert.Error(t, client.PauseWorker("default", "unknown"))
+}
+
+func TestRegisterWorker_OutputNamesMismatch(t *testing.T) {
+	config := &configs.ClientConfig{}
+	config.SetClientID("test-client")
+	config.SetAuthToken("test-token")
+	client, err := apis.NewUnmeshedClient(config)
+	assert.NoError(t, err)
+
+	worker := workers.NewWorker(func(data map[string]interface{}) (string, int, error) {
+		return "", 0, nil
+	}, "worker1")
+	worker.SetOutputNames("message")
+	err = client.RegisterWorker(worker)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has 1 output names but its execution method returns 2 values")
+
+	worker.SetOutputNames("message", "count")
+	assert.NoError(t, client.RegisterWorker(worker))
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func TestTimedOutResponse(t *testing.T) {
+	workRequest := newTestWorkRequest(nil)
+	workRequest.StepID = 7
+	workRequest.StepExecutionID = 11
+
+	workResponse := common.NewWorkResponseBuilder().TimedOutResponse(workRequest, 1500*time.Millisecond)
+	assert.Equal(t, common.StepStatusTimedOut, workResponse.GetStatus())
+	assert.Equal(t, int64(7), workResponse.GetStepID())
+	assert.Equal(t, int64(11), workResponse.GetStepExecutionID())
+	assert.Equal(t, map[string]interface{}{
+		"code":      common.ErrorCodeStepTimedOut,
+		"type":      "TimeoutError",
+		"message":   "step timed out after 1500 ms",
+		"details":   map[string]interface{}{"timeoutMillis": int64(1500)},
+		"retryable": false,
+		"causes":    []string{},
+	}, workResponse.GetOutput()["error"])
+}
+
+func TestFailResponse_PlainErrorIsStructured(t *testing.T) {
+	cause := errors.New("connection reset")
+	workResponse := common.NewWorkResponseBuilder().FailResponse(newTestWorkRequest(nil), fmt.Errorf("fetch failed: %w", cause))
+	assert.Equal(t, map[string]interface{}{
+		"code":      common.ErrorCodeWorkerError,
+		"type":      "Error",
+		"message":   "fetch failed: connection reset",
+		"details":   map[string]interface{}{},
+		"retryable": false,
+		"causes":    []string{"connection reset"},
+	}, workResponse.GetOutput()["error"])
+}
+
+func TestFailResponse_JSONErrorMessageBecomesDetails(t *testing.T) {
+	workResponse := common.NewWorkResponseBuilder().FailResponse(newTestWorkRequest(nil), errors.New(`{"reason":"quota"}`))
+	errorOutput := workResponse.GetOutput()["error"].(map[string]interface{})
+	assert.Equal(t, common.ErrorCodeWorkerError, errorOutput["code"])
+	assert.Equal(t, map[string]interface{}{"reason": "quota"}, errorOutput["details"])
+}
+
+func TestFailResponse_StepError(t *testing.T) {
+	cause := fmt.Errorf("charge declined: %w", errors.New("insufficient funds"))
+	stepErr := &common.StepError{
+		Code:      "PAYMENT_DECLINED",
+		Type:      "PaymentError",
+		Message:   "payment could not be captured",
+		Details:   map[string]interface{}{"orderId": "o-1"},
+		Retryable: false,
+		Cause:     cause,
+	}
+	assert.Equal(t, "PAYMENT_DECLINED: payment could not be captured: charge declined: insufficient funds", stepErr.Error())
+	assert.ErrorIs(t, stepErr, cause)
+
+	workResponse := common.NewWorkResponseBuilder().FailResponse(newTestWorkRequest(nil), fmt.Errorf("worker failed: %w", stepErr))
+	assert.Equal(t, common.StepStatusFailed, workResponse.GetStatus())
+	assert.Equal(t, map[string]interface{}{
+		"code":      "PAYMENT_DECLINED",
+		"type":      "PaymentError",
+		"message":   "payment could not be captured",
+		"details":   map[string]interface{}{"orderId": "o-1"},
+		"retryable": false,
+		"causes":    []string{"charge declined: insufficient funds", "insufficient funds"},
+	}, workResponse.GetOutput()["error"])
+}
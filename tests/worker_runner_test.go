@@ -0,0 +1,412 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	runner "github.com/unmeshed/unmeshed-go-sdk/sdk/apis/runner"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/apis/workers"
+	"github.com/unmeshed/unmeshed-go-sdk/sdk/common"
+)
+
+func newTestWorkRequest(input map[string]interface{}) *common.WorkRequest {
+	workRequest := common.NewWorkRequest()
+	workRequest.StepName = "test-worker"
+	workRequest.SetStepNamespace("default")
+	workRequest.InputParam = input
+	return workRequest
+}
+
+func TestRunWorker_SingleArgument(t *testing.T) {
+	worker := workers.NewWorker(func(data map[string]int) int {
+		return data["a"] + data["b"]
+	}, "sum")
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker,
+		newTestWorkRequest(map[string]interface{}{"a": 1, "b": 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.GetResult())
+}
+
+func TestRunWorker_ContextArgument(t *testing.T) {
+	worker := workers.NewWorker(func(ctx context.Context, data map[string]interface{}) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return "ok", nil
+	}, "ctx-worker")
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.GetResult())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = runner.NewWorkerRunner().RunWorker(ctx, worker, newTestWorkRequest(nil))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseMethodSignature(t *testing.T) {
+	signature, err := workers.ParseMethodSignature(func(ctx context.Context, data map[string]interface{}) error { return nil })
+	assert.NoError(t, err)
+	assert.True(t, signature.HasContext)
+
+	signature, err = workers.ParseMethodSignature(func(data map[string]interface{}) error { return nil })
+	assert.NoError(t, err)
+	assert.False(t, signature.HasContext)
+
+	signature, err = workers.ParseMethodSignature(func(ctx context.Context, workRequest *common.WorkRequest, data map[string]interface{}) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, signature.HasContext)
+	assert.True(t, signature.HasWorkRequest)
+
+	signature, err = workers.ParseMethodSignature(func(workRequest *common.WorkRequest) error { return nil })
+	assert.NoError(t, err)
+	assert.True(t, signature.HasWorkRequest)
+	assert.Nil(t, signature.InputType)
+
+	signature, err = workers.ParseMethodSignature(func(ctx context.Context) error { return nil })
+	assert.NoError(t, err)
+	assert.Nil(t, signature.InputType)
+
+	_, err = workers.ParseMethodSignature(func(a, b map[string]interface{}) error { return nil })
+	assert.Error(t, err)
+
+	_, err = workers.ParseMethodSignature(func(data map[string]interface{}, workRequest *common.WorkRequest) error { return nil })
+	assert.Error(t, err)
+
+	_, err = workers.ParseMethodSignature("not a function")
+	assert.Error(t, err)
+}
+
+type typedSumInput struct {
+	Values []int `json:"values"`
+}
+
+func TestRunWorker_TypedWorker(t *testing.T) {
+	worker := workers.NewTypedWorker("typed-sum", func(ctx context.Context, in typedSumInput) (int, error) {
+		sum := 0
+		for _, v := range in.Values {
+			sum += v
+		}
+		return sum, nil
+	})
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker,
+		newTestWorkRequest(map[string]interface{}{"values": []interface{}{1, 2, 3}}))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, result.GetResult())
+}
+
+func TestRunWorker_TypedWorkerMapInput(t *testing.T) {
+	input := map[string]interface{}{"key": "value"}
+	worker := workers.NewTypedWorker("typed-map", func(ctx context.Context, in map[string]interface{}) (map[string]interface{}, error) {
+		return in, nil
+	})
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(input))
+	assert.NoError(t, err)
+	assert.Equal(t, input, result.GetResult())
+}
+
+func TestRunWorker_PanicIsRecovered(t *testing.T) {
+	worker := workers.NewWorker(func(data map[string]interface{}) string {
+		panic("boom")
+	}, "panicking-worker")
+
+	workRequest := newTestWorkRequest(nil)
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.Nil(t, result)
+
+	var panicErr *common.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotContains(t, panicErr.Stack, "runtime/debug.Stack")
+
+	workResponse := common.NewWorkResponseBuilder().FailResponse(workRequest, err)
+	assert.Equal(t, common.StepStatusFailed, workResponse.GetStatus())
+	assert.Equal(t, "boom", workResponse.GetOutput()["panic"])
+	assert.NotEmpty(t, workResponse.GetOutput()["stackTrace"])
+	errorOutput := workResponse.GetOutput()["error"].(map[string]interface{})
+	assert.Equal(t, common.ErrorCodeWorkerPanic, errorOutput["code"])
+	assert.Equal(t, "PanicError", errorOutput["type"])
+	assert.Equal(t, "boom", errorOutput["message"])
+}
+
+func TestRunWorker_InterceptorOrder(t *testing.T) {
+	var calls []string
+	recorder := func(name string) workers.Interceptor {
+		return func(next workers.Handler) workers.Handler {
+			return func(ctx context.Context, workRequest *common.WorkRequest) (*common.StepResult, error) {
+				calls = append(calls, name)
+				return next(ctx, workRequest)
+			}
+		}
+	}
+
+	worker := workers.NewWorker(func(data map[string]interface{}) string {
+		calls = append(calls, "worker")
+		return "done"
+	}, "intercepted")
+	worker.Use(recorder("worker-1"), recorder("worker-2"))
+
+	workerRunner := runner.NewWorkerRunner()
+	workerRunner.Use(recorder("global-1"), recorder("global-2"))
+
+	result, err := workerRunner.RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "done", result.GetResult())
+	assert.Equal(t, []string{"global-1", "global-2", "worker-1", "worker-2", "worker"}, calls)
+}
+
+func TestRunWorker_InterceptorShortCircuit(t *testing.T) {
+	called := false
+	worker := workers.NewWorker(func(data map[string]interface{}) string {
+		called = true
+		return "done"
+	}, "rejected")
+	worker.Use(func(next workers.Handler) workers.Handler {
+		return func(ctx context.Context, workRequest *common.WorkRequest) (*common.StepResult, error) {
+			if _, ok := workRequest.GetInputParam()["token"]; !ok {
+				return nil, errors.New("missing token")
+			}
+			return next(ctx, workRequest)
+		}
+	})
+
+	_, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.EqualError(t, err, "missing token")
+	assert.False(t, called)
+}
+
+func TestRunWorker_StepContext(t *testing.T) {
+	worker := workers.NewWorker(func(ctx context.Context, data map[string]interface{}) (int64, error) {
+		processIDs := make(chan int64)
+		go func() {
+			processIDs <- common.StepContextFrom(ctx).ProcessID
+		}()
+		return <-processIDs, nil
+	}, "step-context")
+
+	workRequest := newTestWorkRequest(nil)
+	workRequest.ProcessID = 99
+	workRequest.StepRef = "ref1"
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(99), result.GetResult())
+	assert.Nil(t, common.StepContextFrom(context.Background()))
+}
+
+func TestRunWorker_RetryPolicy(t *testing.T) {
+	var attempts []int
+	worker := workers.NewWorker(func(ctx context.Context, data map[string]interface{}) (string, error) {
+		attempt := common.StepContextFrom(ctx).Attempt
+		attempts = append(attempts, attempt)
+		if attempt < 3 {
+			return "", errors.New("temporarily unavailable")
+		}
+		return "ok", nil
+	}, "flaky")
+	worker.SetRetryPolicy(&workers.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2})
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.GetResult())
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRunWorker_RetryPolicyExhausted(t *testing.T) {
+	permanent := errors.New("invalid input")
+	calls := 0
+	worker := workers.NewWorker(func(data map[string]interface{}) (string, error) {
+		calls++
+		if calls == 3 {
+			return "", permanent
+		}
+		return "", errors.New("temporarily unavailable")
+	}, "failing")
+	worker.SetRetryPolicy(&workers.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, permanent)
+		},
+	})
+
+	workRequest := newTestWorkRequest(nil)
+	_, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.Equal(t, 3, calls)
+	assert.ErrorIs(t, err, permanent)
+
+	var retryErr *common.RetryError
+	assert.ErrorAs(t, err, &retryErr)
+	assert.Len(t, retryErr.Attempts, 3)
+
+	workResponse := common.NewWorkResponseBuilder().FailResponse(workRequest, err)
+	errorOutput := workResponse.GetOutput()["error"].(map[string]interface{})
+	assert.Equal(t, common.ErrorCodeWorkerError, errorOutput["code"])
+	assert.Equal(t, "invalid input", errorOutput["message"])
+	assert.Len(t, workResponse.GetOutput()["attempts"], 3)
+}
+
+func TestRunWorker_RetriesTakeRateLimitTokens(t *testing.T) {
+	calls := 0
+	worker := workers.NewWorker(func(data map[string]interface{}) (string, error) {
+		calls++
+		return "", errors.New("temporarily unavailable")
+	}, "rate-limited")
+	worker.SetRetryPolicy(&workers.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+	worker.SetRateLimit(2, time.Hour, 2)
+	// The first execution takes its token when it is polled.
+	assert.Equal(t, 1, worker.GetRateLimiter().TryAcquire(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := runner.NewWorkerRunner().RunWorker(ctx, worker, newTestWorkRequest(nil))
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, worker.GetRateLimiter().Available())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &workers.RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 900*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, time.Second, policy.Backoff(4))
+
+	assert.False(t, policy.IsRetryable(common.NewPanicError("boom", nil)))
+	assert.False(t, policy.IsRetryable(context.DeadlineExceeded))
+	assert.True(t, policy.IsRetryable(errors.New("temporarily unavailable")))
+}
+
+func TestRetryPolicy_StepErrorRetryable(t *testing.T) {
+	policy := &workers.RetryPolicy{MaxAttempts: 3}
+	assert.True(t, policy.IsRetryable(&common.StepError{Code: "RATE_LIMITED", Retryable: true}))
+	assert.False(t, policy.IsRetryable(common.NewStepError("INVALID_INPUT", "amount must be positive")))
+}
+
+func TestRunWorker_WorkRequestArgument(t *testing.T) {
+	worker := workers.NewWorker(func(ctx context.Context, workRequest *common.WorkRequest, in typedSumInput) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"processId": workRequest.GetProcessID(),
+			"priority":  workRequest.GetPriority(),
+			"attempt":   common.StepContextFrom(ctx).Attempt,
+			"count":     len(in.Values),
+		}, nil
+	}, "metadata")
+
+	workRequest := newTestWorkRequest(map[string]interface{}{"values": []interface{}{1, 2}})
+	workRequest.ProcessID = 42
+	workRequest.Priority = 3
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"processId": int64(42), "priority": int64(3), "attempt": 1, "count": 2}, result.GetResult())
+
+	worker = workers.NewWorker(func(workRequest *common.WorkRequest) string {
+		return workRequest.StepRef
+	}, "metadata-only")
+	workRequest.StepRef = "ref1"
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "ref1", result.GetResult())
+}
+
+func TestRunWorker_OutputNames(t *testing.T) {
+	worker := workers.NewWorker(func(data map[string]interface{}) (string, int, error) {
+		return "processed", len(data), nil
+	}, "multi-output")
+	worker.SetOutputNames("message", "count")
+
+	workRequest := newTestWorkRequest(map[string]interface{}{"a": 1, "b": 2})
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"message": "processed", "count": 2}, result.GetResult())
+
+	typed := workers.NewTypedWorker("typed-output", func(ctx context.Context, in map[string]interface{}) ([]string, error) {
+		return []string{"a", "b"}, nil
+	})
+	typed.SetOutputNames("items")
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), typed, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"items": []string{"a", "b"}}, result.GetResult())
+}
+
+func TestRunWorker_OutputNamesKeepStepResult(t *testing.T) {
+	worker := workers.NewWorker(func(data map[string]interface{}) (*common.StepResult, error) {
+		return &common.StepResult{Result: "waiting", KeepRunning: true, RescheduleAfterSeconds: 30}, nil
+	}, "rescheduling")
+	worker.SetOutputNames("status")
+
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.True(t, result.KeepRunning)
+	assert.Equal(t, 30, result.RescheduleAfterSeconds)
+	assert.Equal(t, "waiting", result.GetResult())
+
+	typed := workers.NewTypedWorker("deferring", func(ctx context.Context, in map[string]interface{}) (*common.StepResult, error) {
+		return common.NewDeferredStepResult(), nil
+	})
+	typed.SetOutputNames("approval")
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), typed, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.True(t, result.Deferred)
+}
+
+func TestRunWorker_RawAndScalarInputs(t *testing.T) {
+	workRequest := newTestWorkRequest(map[string]interface{}{"name": "unmeshed"})
+
+	raw := workers.NewWorker(func(in json.RawMessage) string { return string(in) }, "raw")
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), raw, workRequest)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"unmeshed"}`, result.GetResult().(string))
+
+	bytes := workers.NewWorker(func(in []byte) int { return len(in) }, "bytes")
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), bytes, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, len(`{"name":"unmeshed"}`), result.GetResult())
+
+	scalar := workers.NewWorker(func(name string) string { return "hello " + name }, "scalar")
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), scalar, workRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello unmeshed", result.GetResult())
+
+	count := workers.NewTypedWorker("typed-scalar", func(ctx context.Context, count int64) (int64, error) {
+		return count * 2, nil
+	})
+	result, err = runner.NewWorkerRunner().RunWorker(context.Background(), count, newTestWorkRequest(map[string]interface{}{"count": 21}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), result.GetResult())
+
+	_, err = runner.NewWorkerRunner().RunWorker(context.Background(), scalar, newTestWorkRequest(map[string]interface{}{"a": "1", "b": "2"}))
+	assert.Error(t, err)
+}
+
+func TestRunWorker_NoInput(t *testing.T) {
+	worker := workers.NewWorker(func() string { return "pong" }, "ping")
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "pong", result.GetResult())
+}
+
+func TestRunWorker_NilResult(t *testing.T) {
+	worker := workers.NewWorker(func(ctx context.Context) (interface{}, error) { return nil, nil }, "ping")
+	result, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, result.GetResult())
+}
+
+func TestStepContext_ReportProgressWithoutClient(t *testing.T) {
+	worker := workers.NewWorker(func(ctx context.Context, data map[string]interface{}) error {
+		return common.StepContextFrom(ctx).ReportProgress(map[string]interface{}{"percent": 50})
+	}, "progress")
+
+	_, err := runner.NewWorkerRunner().RunWorker(context.Background(), worker, newTestWorkRequest(nil))
+	assert.EqualError(t, err, "progress reporting is not available for this step")
+}